@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	t "time"
+)
+
+const achievementsFilename = "achievements.json"
+
+// achievementCondition checks if the achievement is earned after the event occurred
+type achievementCondition func(progress *achievementTracker, event gameEvent) bool
+
+// Achievement is the goal the player can reach during the game
+type Achievement struct {
+	ID          string
+	Title       string
+	Description string
+	condition   achievementCondition
+}
+
+// PlayerAchievements maps the player name to the unlock time of each of the player's achievements
+type PlayerAchievements map[string]map[string]t.Time
+
+var achievementsList = []*Achievement{
+	&Achievement{
+		ID:          "first-bite",
+		Title:       "First bite",
+		Description: "Eat the first food",
+		condition: func(progress *achievementTracker, event gameEvent) bool {
+			return event.name == foodEatenEvent
+		}},
+
+	&Achievement{
+		ID:          "length-50",
+		Title:       "Anaconda",
+		Description: "Grow to the length of 50",
+		condition: func(progress *achievementTracker, event gameEvent) bool {
+			return event.name == foodEatenEvent && event.length >= 50
+		}},
+
+	&Achievement{
+		ID:          "bonus-streak-5",
+		Title:       "Gourmet",
+		Description: "Eat 5 bonus food in a row",
+		condition: func(progress *achievementTracker, event gameEvent) bool {
+			return progress.bonusStreak >= 5
+		}},
+
+	&Achievement{
+		ID:          "survive-5-minutes",
+		Title:       "Survivor",
+		Description: "Survive for 5 minutes",
+		condition: func(progress *achievementTracker, event gameEvent) bool {
+			return event.name == tickEvent && t.Duration(event.tick)*tickDuration >= 5*t.Minute
+		}},
+
+	&Achievement{
+		ID:          "straight-30",
+		Title:       "Straight shooter",
+		Description: "Move 30 tiles without turning",
+		condition: func(progress *achievementTracker, event gameEvent) bool {
+			return progress.straightTiles >= 30
+		}},
+
+	&Achievement{
+		ID:          "score-1000",
+		Title:       "High roller",
+		Description: "Score 1000 points in a single game",
+		condition: func(progress *achievementTracker, event gameEvent) bool {
+			return event.name == foodEatenEvent && score >= 1000
+		}}}
+
+// achievementTracker evaluates the achievement conditions on every game event
+type achievementTracker struct {
+	bonusStreak   int
+	straightTiles int
+	// unlocked contains the achievements earned during the current game
+	unlocked []*Achievement
+	// known contains the achievements unlocked by the current player before, they are not announced again
+	known map[string]bool
+	// announce is called once the achievement is unlocked
+	announce func(achievement *Achievement)
+}
+
+var achievementProgress = &achievementTracker{
+	known: make(map[string]bool),
+	announce: func(achievement *Achievement) {
+		toasts.Push("Achievement unlocked: " + achievement.Title)
+	}}
+
+func (progress *achievementTracker) handleGameEvent(event gameEvent) {
+	switch event.name {
+	case gameStartedEvent:
+		progress.reset(lastPlayerName)
+		return
+	case foodEatenEvent:
+		if event.foodKind == bonusFoodKind {
+			progress.bonusStreak++
+		} else {
+			progress.bonusStreak = 0
+		}
+	case turnEvent:
+		progress.straightTiles = 0
+	case tickEvent:
+		progress.straightTiles++
+	}
+
+	for _, achievement := range achievementsList {
+		if !progress.isUnlocked(achievement) && achievement.condition(progress, event) {
+			log.Printf("Achievement unlocked: %s", achievement.ID)
+			progress.unlocked = append(progress.unlocked, achievement)
+			if !progress.known[achievement.ID] {
+				progress.announce(achievement)
+			}
+		}
+	}
+}
+
+// reset starts tracking of the new game played by the specified player, if already known
+func (progress *achievementTracker) reset(playerName string) {
+	progress.bonusStreak = 0
+	progress.straightTiles = 0
+	progress.unlocked = nil
+	progress.known = make(map[string]bool)
+
+	if playerName == "" {
+		return
+	}
+	achievements, loadError := LoadAchievements()
+	if loadError != nil {
+		log.Println("Error loading achievements: ", loadError)
+		return
+	}
+	for id := range achievements[playerName] {
+		progress.known[id] = true
+	}
+}
+
+func (progress *achievementTracker) isUnlocked(achievement *Achievement) bool {
+	for _, unlocked := range progress.unlocked {
+		if unlocked == achievement {
+			return true
+		}
+	}
+	return false
+}
+
+// Unlocked returns the identifiers of the achievements earned during the current game
+func (progress *achievementTracker) Unlocked() []string {
+	ids := []string{}
+	for _, achievement := range progress.unlocked {
+		ids = append(ids, achievement.ID)
+	}
+	return ids
+}
+
+func achievementsPath() string {
+	return filepath.Join(dataDir(), achievementsFilename)
+}
+
+// LoadAchievements reads the unlocked achievements of all of the players
+func LoadAchievements() (PlayerAchievements, error) {
+	lock, lockError := acquireFileLock(achievementsPath()+".lock", false)
+	if lockError != nil {
+		return nil, lockError
+	}
+	defer lock.release()
+
+	return readAchievements(achievementsPath())
+}
+
+func readAchievements(path string) (PlayerAchievements, error) {
+	achievements := make(PlayerAchievements)
+	content, readError := ioutil.ReadFile(path)
+	if os.IsNotExist(readError) {
+		return achievements, nil
+	}
+	if readError != nil {
+		return nil, readError
+	}
+
+	if parseError := json.Unmarshal(content, &achievements); parseError != nil {
+		return nil, parseError
+	}
+	return achievements, nil
+}
+
+// SaveUnlockedAchievements adds the achievements to the ones already unlocked by the player
+func SaveUnlockedAchievements(playerName string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if mkdirError := os.MkdirAll(dataDir(), 0755); mkdirError != nil {
+		return mkdirError
+	}
+
+	lock, lockError := acquireFileLock(achievementsPath()+".lock", true)
+	if lockError != nil {
+		return lockError
+	}
+	defer lock.release()
+
+	achievements, readError := readAchievements(achievementsPath())
+	if readError != nil {
+		return readError
+	}
+
+	if achievements[playerName] == nil {
+		achievements[playerName] = make(map[string]t.Time)
+	}
+	now := t.Now()
+	for _, id := range ids {
+		if _, unlocked := achievements[playerName][id]; !unlocked {
+			achievements[playerName][id] = now
+		}
+	}
+
+	content, marshalError := json.MarshalIndent(achievements, "", "  ")
+	if marshalError != nil {
+		return marshalError
+	}
+	return writeFileAtomic(achievementsPath(), content, "")
+}
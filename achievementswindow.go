@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	t "time"
+
+	"github.com/VAlux/GSnake/widget"
+	gc "github.com/rthornton128/goncurses"
+)
+
+const (
+	achievementsWindowTitle  = "Achievements"
+	achievementsWindowWidth  = 60
+	achievementsWindowHeight = 21
+	achievementsWindowHelp   = "Left/Right: player  q: close"
+	achievementUnlockedMark  = "[x]"
+	achievementLockedMark    = "[ ]"
+)
+
+// AchievementsWindow lists all of the achievements with the unlock status of the selected player
+type AchievementsWindow struct {
+	achievements PlayerAchievements
+	names        []string
+	playerIndex  int
+	dialog       *widget.Dialog
+	label        *widget.Label
+}
+
+// NewAchievementsWindow creates the achievements window starting with the specified player
+func NewAchievementsWindow(achievements PlayerAchievements, playerName string) *AchievementsWindow {
+	achievementsWindow := &AchievementsWindow{achievements: achievements}
+	for name := range achievements {
+		achievementsWindow.names = append(achievementsWindow.names, name)
+	}
+	sort.Strings(achievementsWindow.names)
+
+	for idx, name := range achievementsWindow.names {
+		if name == playerName {
+			achievementsWindow.playerIndex = idx
+		}
+	}
+	return achievementsWindow
+}
+
+// Open creates the window as a child of specified window
+func (achievementsWindow *AchievementsWindow) Open(s *gc.Window) error {
+	achievementsWindow.label = widget.NewLabel()
+	dialog, dialogError := widget.NewDialog(s, achievementsWindowHeight, achievementsWindowWidth, achievementsWindowTitle, achievementsWindow.label)
+	if dialogError != nil {
+		return dialogError
+	}
+
+	dialog.SetFooter(achievementsWindowHelp)
+	achievementsWindow.dialog = dialog
+	return nil
+}
+
+// Close removes the window from the screen
+func (achievementsWindow *AchievementsWindow) Close() {
+	achievementsWindow.dialog.Close()
+}
+
+// HandleKey applies the key action. Returns false if the window should be closed
+func (achievementsWindow *AchievementsWindow) HandleKey(key gc.Key) bool {
+	playersAmount := len(achievementsWindow.names)
+	switch key {
+	case gc.KEY_LEFT:
+		if playersAmount > 0 {
+			achievementsWindow.playerIndex = (achievementsWindow.playerIndex + playersAmount - 1) % playersAmount
+		}
+	case gc.KEY_RIGHT:
+		if playersAmount > 0 {
+			achievementsWindow.playerIndex = (achievementsWindow.playerIndex + 1) % playersAmount
+		}
+	case 'q', escapeKey, gc.KEY_RETURN:
+		return false
+	}
+	return true
+}
+
+// lines returns the achievement list with the unlock marks of the selected player
+func (achievementsWindow *AchievementsWindow) lines() []string {
+	unlocked := map[string]t.Time{}
+	header := "No achievements unlocked yet"
+	if len(achievementsWindow.names) > 0 {
+		name := achievementsWindow.names[achievementsWindow.playerIndex]
+		unlocked = achievementsWindow.achievements[name]
+		header = fmt.Sprintf("Player: %s  (%d of %d)", name, achievementsWindow.playerIndex+1, len(achievementsWindow.names))
+	}
+
+	lines := []string{header, ""}
+	for _, achievement := range achievementsList {
+		title := fmt.Sprintf("%s %s", achievementLockedMark, achievement.Title)
+		if unlockTime, isUnlocked := unlocked[achievement.ID]; isUnlocked {
+			title = fmt.Sprintf("%s %s (%s)", achievementUnlockedMark, achievement.Title, unlockTime.Format("2006-01-02"))
+		}
+		lines = append(lines, title, "    "+achievement.Description)
+	}
+	return lines
+}
+
+func (achievementsWindow *AchievementsWindow) draw() {
+	achievementsWindow.label.SetText(achievementsWindow.lines()...)
+	achievementsWindow.dialog.Draw()
+}
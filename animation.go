@@ -1,10 +1,16 @@
 package main
 
+import "math"
+
 // Animation declares a way of how to manipulate the internal animation frames
 type Animation interface {
 	NextFrame() string
 	CurrentFrame() string
 	MoveFrameIndex()
+	// Finished checks if the one-shot animation has shown its last frame, the looping animation never finishes
+	Finished() bool
+	// Progress returns the share of the animation played from 0 to 1, the looping animation starts over from 0
+	Progress() float64
 
 	framesAmount() int
 	hasNextFrame() bool
@@ -13,17 +19,68 @@ type Animation interface {
 type animation struct {
 	frames            []string
 	currentFrameIndex int
-	frameDuration     int
-	currentFrameTime  int
+	// frameDurations are the amounts of ticks the frames are shown, the frames of zero duration are skipped
+	frameDurations   []int
+	currentFrameTime int
+	// looping animation starts over after the last frame, the one-shot one stops at it
+	looping  bool
+	finished bool
+	// elapsedTime is the amount of ticks since the animation started over
+	elapsedTime int
 }
 
-// NewAnimation creates new animation object with specified frames array
+// Easing maps the linear progress from 0 to 1 to the eased one, to speed up or slow down the animation
+type Easing func(progress float64) float64
+
+// Easing functions of the animations and the movements
+var (
+	linearEasing Easing = func(progress float64) float64 { return progress }
+	easeIn       Easing = func(progress float64) float64 { return progress * progress }
+	easeOut      Easing = func(progress float64) float64 { return 1 - (1-progress)*(1-progress) }
+	easeInOut    Easing = func(progress float64) float64 {
+		if progress < 0.5 {
+			return 2 * progress * progress
+		}
+		return 1 - 2*(1-progress)*(1-progress)
+	}
+)
+
+// NewAnimation creates new looping animation object with specified frames array, every frame shown for the duration
 func NewAnimation(frames []string, duration int) Animation {
+	return NewTimedAnimation(frames, uniformDurations(len(frames), duration), true)
+}
+
+// NewOneShotAnimation creates the animation played once, every frame shown for the duration
+func NewOneShotAnimation(frames []string, duration int) Animation {
+	return NewTimedAnimation(frames, uniformDurations(len(frames), duration), false)
+}
+
+// NewTimedAnimation creates the animation with the own duration of every frame
+func NewTimedAnimation(frames []string, durations []int, looping bool) Animation {
 	return &animation{
 		frames:            frames,
 		currentFrameIndex: 0,
-		frameDuration:     duration,
-		currentFrameTime:  0}
+		frameDurations:    durations,
+		currentFrameTime:  0,
+		looping:           looping}
+}
+
+// NewEasedAnimation creates the one-shot animation lasting the duration, with the frames switched according to the easing
+func NewEasedAnimation(frames []string, duration int, easing Easing) Animation {
+	durations := make([]int, len(frames))
+	for tick := 0; tick < duration; tick++ {
+		frame := int(easing(float64(tick)/float64(duration)) * float64(len(frames)))
+		durations[minInt(maxInt(frame, 0), len(frames)-1)]++
+	}
+	return NewTimedAnimation(frames, durations, false)
+}
+
+func uniformDurations(amount int, duration int) []int {
+	durations := make([]int, amount)
+	for idx := range durations {
+		durations[idx] = duration
+	}
+	return durations
 }
 
 func (a *animation) framesAmount() int {
@@ -34,23 +91,51 @@ func (a *animation) hasNextFrame() bool {
 	return a.currentFrameIndex < a.framesAmount()-1
 }
 
+func (a *animation) totalDuration() int {
+	total := 0
+	for _, duration := range a.frameDurations {
+		total += duration
+	}
+	return total
+}
+
 // MoveFrameIndex moves the current frame caret to the next frame.
-// If there are no frames left in the sequence - caret will be reset and point to the 0 frame
+// If there are no frames left in the sequence - caret will be reset and point to the 0 frame,
+// the one-shot animation stays at the last frame and finishes instead
 func (a *animation) MoveFrameIndex() {
-	if a.currentFrameTime < a.frameDuration-1 {
+	if a.finished || a.framesAmount() == 0 {
+		return
+	}
+
+	a.elapsedTime++
+	if a.currentFrameTime < a.frameDurations[a.currentFrameIndex]-1 {
 		a.currentFrameTime++
-	} else {
-		a.currentFrameTime = 0
-		if a.hasNextFrame() {
+		return
+	}
+
+	a.currentFrameTime = 0
+	for skipped := 0; skipped < a.framesAmount(); skipped++ {
+		switch {
+		case a.hasNextFrame():
 			a.currentFrameIndex++
-		} else {
+		case a.looping:
 			a.currentFrameIndex = 0
+			a.elapsedTime = 0
+		default:
+			a.finished = true
+			return
+		}
+		if a.frameDurations[a.currentFrameIndex] > 0 {
+			return
 		}
 	}
 }
 
 // CurrentFrame obtains current frame from the animation sequence
 func (a *animation) CurrentFrame() string {
+	for a.frameDurations[a.currentFrameIndex] == 0 && a.hasNextFrame() {
+		a.currentFrameIndex++
+	}
 	return a.frames[a.currentFrameIndex]
 }
 
@@ -60,3 +145,14 @@ func (a *animation) NextFrame() string {
 	a.MoveFrameIndex()
 	return a.CurrentFrame()
 }
+
+func (a *animation) Finished() bool {
+	return a.finished
+}
+
+func (a *animation) Progress() float64 {
+	if a.finished {
+		return 1
+	}
+	return math.Min(float64(a.elapsedTime)/float64(maxInt(a.totalDuration(), 1)), 1)
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	t "time"
+)
+
+const cliUsage = `Usage:
+  gsnake                                  start the game
+  gsnake scores list [-n N] [-player NAME] [-mode MODE]
+  gsnake scores export [-format csv|json|jsonl] [-o FILE]
+  gsnake scores import [-format auto|csv|json|jsonl|hsc] FILE...
+  gsnake scores merge -o FILE [-format auto|csv|json|jsonl|hsc] FILE...
+  gsnake scores prune [-top N] [-last M] [-player NAME] [-before YYYY-MM-DD]
+  gsnake scores verify [-format auto|csv|json|jsonl|hsc] [FILE...]
+  gsnake leaderboard serve [--addr :8080] [--secret SECRET] [--store KIND] [--data DIR]
+`
+
+// errUsage signals that the command line is malformed and the usage should be printed
+var errUsage = errors.New("invalid command line")
+
+// commandHandler executes the subcommand with the remaining command line arguments
+type commandHandler func(args []string, stdout io.Writer) error
+
+var scoresCommands = map[string]commandHandler{
+	"list":   scoresListCommand,
+	"export": scoresExportCommand,
+	"import": scoresImportCommand,
+	"merge":  scoresMergeCommand,
+	"prune":  scoresPruneCommand,
+	"verify": scoresVerifyCommand}
+
+var leaderboardCommands = map[string]commandHandler{
+	"serve": leaderboardServeCommand}
+
+var commandGroups = map[string]map[string]commandHandler{
+	"scores":      scoresCommands,
+	"leaderboard": leaderboardCommands}
+
+// runCommand executes the command line subcommand without starting ncurses. Returns the process exit code
+func runCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	commandError := dispatchCommand(args, stdout)
+	if commandError == nil {
+		return 0
+	}
+
+	if commandError == errUsage || commandError == flag.ErrHelp {
+		fmt.Fprint(stderr, cliUsage)
+		return 2
+	}
+
+	fmt.Fprintln(stderr, "gsnake:", commandError)
+	return 1
+}
+
+func dispatchCommand(args []string, stdout io.Writer) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	handler, ok := commandGroups[args[0]][args[1]]
+	if !ok {
+		return errUsage
+	}
+	return handler(args[2:], stdout)
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+func openScoreStore() (ScoreStore, error) {
+	return NewScoreStore(gameConfig.ScoreStore, dataDir(), gameConfig.ScoreRetention)
+}
+
+// scoreStoreFile returns the path of the file used by the configured score store
+func scoreStoreFile() (string, error) {
+	switch gameConfig.ScoreStore {
+	case fileScoreStoreKind, "":
+		return filepath.Join(dataDir(), highScoreFilename), nil
+	case jsonlScoreStoreKind:
+		return filepath.Join(dataDir(), jsonLinesScoreFilename), nil
+	default:
+		return "", errors.New("Score store has no file: " + gameConfig.ScoreStore)
+	}
+}
+
+func scoresListCommand(args []string, stdout io.Writer) error {
+	flags := newFlagSet("scores list")
+	amount := flags.Int("n", 10, "amount of the best scores to show, 0 for all")
+	player := flags.String("player", "", "show only the scores of the player")
+	mode := flags.String("mode", "", "show only the scores of the game mode")
+	if parseError := flags.Parse(args); parseError != nil {
+		return parseError
+	}
+
+	store, storeError := openScoreStore()
+	if storeError != nil {
+		return storeError
+	}
+
+	scores, loadError := store.Top(*amount, ScoreFilter{PlayerName: *player, Mode: *mode})
+	if loadError != nil {
+		return loadError
+	}
+
+	fmt.Fprintln(stdout, HighScoreTableHeader())
+	for idx := range scores {
+		fmt.Fprintln(stdout, scores[idx].TableRow())
+	}
+	return nil
+}
+
+func scoresExportCommand(args []string, stdout io.Writer) error {
+	flags := newFlagSet("scores export")
+	format := flags.String("format", csvScoreFormat, "export format: csv, json or jsonl")
+	output := flags.String("o", "", "output file, standard output if not specified")
+	if parseError := flags.Parse(args); parseError != nil {
+		return parseError
+	}
+
+	store, storeError := openScoreStore()
+	if storeError != nil {
+		return storeError
+	}
+
+	scores, loadError := store.Top(0, ScoreFilter{})
+	if loadError != nil {
+		return loadError
+	}
+
+	if *output == "" {
+		return writeScores(stdout, scores, *format)
+	}
+	return writeScoresToFile(*output, scores, *format)
+}
+
+func writeScoresToFile(path string, scores HighScores, format string) error {
+	format, formatError := scoreFormatOf(path, format)
+	if formatError != nil {
+		return formatError
+	}
+
+	file, createError := os.Create(path)
+	if createError != nil {
+		return createError
+	}
+
+	writeError := writeScores(file, scores, format)
+	closeError := file.Close()
+	if writeError != nil {
+		return writeError
+	}
+	return closeError
+}
+
+func scoresImportCommand(args []string, stdout io.Writer) error {
+	flags := newFlagSet("scores import")
+	format := flags.String("format", autoScoreFormat, "input format: auto, csv, json, jsonl or hsc")
+	if parseError := flags.Parse(args); parseError != nil {
+		return parseError
+	}
+	if flags.NArg() == 0 {
+		return errUsage
+	}
+
+	store, storeError := openScoreStore()
+	if storeError != nil {
+		return storeError
+	}
+
+	existing, loadError := store.Top(0, ScoreFilter{})
+	if loadError != nil && !os.IsNotExist(loadError) {
+		return loadError
+	}
+
+	// the scores are imported at once, so the retention policy treats the imported scores like the existing ones
+	imported := HighScores{}
+	for _, path := range flags.Args() {
+		scores, readError := readScoresFile(path, *format)
+		if readError != nil {
+			return fmt.Errorf("%s: %s", path, readError)
+		}
+
+		newScores := newScoresOnly(existing, scores)
+		existing = append(existing, newScores...)
+		imported = append(imported, newScores...)
+		fmt.Fprintf(stdout, "%s: %d scores read, %d new\n", path, len(scores), len(newScores))
+	}
+
+	kept, importError := store.Import(imported)
+	if importError != nil {
+		return importError
+	}
+	fmt.Fprintf(stdout, "%d scores imported, %d kept by the retention policy\n", len(imported), kept)
+	return nil
+}
+
+func scoresMergeCommand(args []string, stdout io.Writer) error {
+	flags := newFlagSet("scores merge")
+	format := flags.String("format", autoScoreFormat, "input format: auto, csv, json, jsonl or hsc")
+	output := flags.String("o", "", "merged output file, its format is detected by the extension")
+	if parseError := flags.Parse(args); parseError != nil {
+		return parseError
+	}
+	if flags.NArg() == 0 || *output == "" {
+		return errUsage
+	}
+
+	lists := []HighScores{}
+	total := 0
+	for _, path := range flags.Args() {
+		scores, readError := readScoresFile(path, *format)
+		if readError != nil {
+			return fmt.Errorf("%s: %s", path, readError)
+		}
+		lists = append(lists, scores)
+		total += len(scores)
+	}
+
+	merged := mergeScores(lists...)
+	if writeError := writeScoresToFile(*output, merged, autoScoreFormat); writeError != nil {
+		return writeError
+	}
+
+	fmt.Fprintf(stdout, "%d scores merged into %s, %d duplicates dropped\n", len(merged), *output, total-len(merged))
+	return nil
+}
+
+func scoresPruneCommand(args []string, stdout io.Writer) error {
+	flags := newFlagSet("scores prune")
+	top := flags.Int("top", gameConfig.ScoreRetention.TopPerCategory, "amount of the best scores kept per category, 0 to keep them by -last only")
+	last := flags.Int("last", gameConfig.ScoreRetention.LastPerPlayer, "amount of the latest scores kept per player, 0 to keep them by -top only")
+	player := flags.String("player", "", "delete all of the scores of the player instead")
+	before := flags.String("before", "", "delete all of the scores made before the date (YYYY-MM-DD, local time) instead, the scores of the date itself are kept")
+	if parseError := flags.Parse(args); parseError != nil {
+		return parseError
+	}
+
+	store, storeError := openScoreStore()
+	if storeError != nil {
+		return storeError
+	}
+
+	if *player == "" && *before == "" {
+		removed, compactError := store.Compact(RetentionPolicy{TopPerCategory: *top, LastPerPlayer: *last})
+		if compactError != nil {
+			return compactError
+		}
+		fmt.Fprintf(stdout, "%d scores pruned\n", removed)
+		return nil
+	}
+
+	filter := ScoreFilter{PlayerName: *player}
+	if *before != "" {
+		date, parseError := t.ParseInLocation("2006-01-02", *before, t.Local)
+		if parseError != nil {
+			return parseError
+		}
+		// Until is inclusive, so the scores made exactly at the midnight of the date are kept
+		filter.Until = date.Add(-1)
+	}
+
+	removed, deleteError := store.Delete(filter)
+	if deleteError != nil {
+		return deleteError
+	}
+	fmt.Fprintf(stdout, "%d scores deleted\n", removed)
+	return nil
+}
+
+func scoresVerifyCommand(args []string, stdout io.Writer) error {
+	flags := newFlagSet("scores verify")
+	format := flags.String("format", autoScoreFormat, "input format: auto, csv, json, jsonl or hsc")
+	if parseError := flags.Parse(args); parseError != nil {
+		return parseError
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		storeFile, storeFileError := scoreStoreFile()
+		if storeFileError != nil {
+			return storeFileError
+		}
+		paths = []string{storeFile}
+	}
+
+	damaged := 0
+	for _, path := range paths {
+		report, verifyError := verifyScoresFile(path, *format)
+		if verifyError != nil {
+			return fmt.Errorf("%s: %s", path, verifyError)
+		}
+
+		fmt.Fprintf(stdout, "%s: %d valid, %d damaged\n", path, report.Valid, report.Damaged)
+		for _, problem := range report.Problems {
+			fmt.Fprintln(stdout, "  "+problem)
+		}
+		damaged += report.Damaged
+	}
+
+	if damaged > 0 {
+		return fmt.Errorf("%d damaged scores found", damaged)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const applicationDirName = "gsnake"
+const configFilename = "config.json"
+
+// Config contains the user adjustable game settings, persisted as JSON in the XDG config directory
+type Config struct {
+	// ScoreStore selects the high score backend: "file", "jsonl" or "memory"
+	ScoreStore string `json:"scoreStore"`
+	// ScoringMode selects the scoring strategy: "classic", "combo" or "time-attack"
+	ScoringMode string `json:"scoringMode"`
+	// Countdown is the amount of seconds the game is frozen after it is started or resumed, 0 to disable
+	Countdown int `json:"countdown"`
+	// Theme is the name of the theme the game is drawn with
+	Theme string `json:"theme"`
+	// HighContrast draws the game objects with the distinct glyphs of the high-contrast theme in the colors of the theme
+	HighContrast bool `json:"highContrast"`
+	// CueFile is the file the text cues for the screen readers are appended to, empty to disable
+	CueFile string `json:"cueFile,omitempty"`
+	// AudibleCues rings the terminal bell on the danger ahead and the game over, if the cues are enabled
+	AudibleCues bool `json:"audibleCues"`
+	// RememberPlayerName skips the player name input, the games are recorded under PlayerName
+	RememberPlayerName bool `json:"rememberPlayerName"`
+	// PlayerName is the remembered name of the player
+	PlayerName string `json:"playerName,omitempty"`
+	// ScoreRetention limits the amount of the stored high scores
+	ScoreRetention RetentionPolicy `json:"scoreRetention"`
+	// LeaderboardURL is the address of the leaderboard server the scores are submitted to, empty to disable
+	LeaderboardURL string `json:"leaderboardUrl,omitempty"`
+	// LeaderboardSecret is shared by the players and the leaderboard server to sign the submissions
+	LeaderboardSecret string `json:"leaderboardSecret,omitempty"`
+}
+
+var gameConfig = defaultConfig()
+
+func defaultConfig() *Config {
+	return &Config{
+		ScoreStore:     fileScoreStoreKind,
+		ScoringMode:    classicScoringMode,
+		Countdown:      defaultCountdown,
+		Theme:          classicThemeName,
+		ScoreRetention: defaultRetentionPolicy()}
+}
+
+// xdgDir resolves the directory from the XDG environment variable, falling back to the specified path in the home dir
+func xdgDir(envVariable string, homeFallback string) string {
+	if dir := os.Getenv(envVariable); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, applicationDirName)
+	}
+
+	home, homeError := os.UserHomeDir()
+	if homeError != nil {
+		log.Println("Error resolving the home directory, using the working directory instead:", homeError)
+		return "."
+	}
+	return filepath.Join(home, homeFallback, applicationDirName)
+}
+
+// configDir returns the directory containing the game configuration
+func configDir() string {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// dataDir returns the directory containing the game data, like the high scores
+func dataDir() string {
+	return xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// LoadConfig reads the configuration file. Missing file or settings are replaced by defaults
+func LoadConfig() *Config {
+	config := defaultConfig()
+	path := filepath.Join(configDir(), configFilename)
+
+	content, readError := ioutil.ReadFile(path)
+	if readError != nil {
+		if !os.IsNotExist(readError) {
+			log.Println("Error reading config file, using defaults:", readError)
+		}
+		return config
+	}
+
+	if parseError := json.Unmarshal(content, config); parseError != nil {
+		log.Println("Error parsing config file, using defaults:", parseError)
+		return defaultConfig()
+	}
+
+	log.Printf("Config loaded from file: %s", path)
+	return config
+}
+
+// Save writes the configuration to the config file
+func (config *Config) Save() error {
+	dir := configDir()
+	if mkdirError := os.MkdirAll(dir, 0755); mkdirError != nil {
+		return mkdirError
+	}
+
+	content, marshalError := json.MarshalIndent(config, "", "  ")
+	if marshalError != nil {
+		return marshalError
+	}
+
+	return writeFileAtomic(filepath.Join(dir, configFilename), content, "")
+}
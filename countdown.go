@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+const defaultCountdown = 3
+
+// maxCountdown is the longest countdown, so it fits into a single digit
+const maxCountdown = 9
+
+// countdownMenuSettings are the countdowns the Settings menu switches between, 0 disables the countdown
+var countdownMenuSettings = []int{0, 1, 2, 3}
+
+// nextCountdownSetting returns the countdown following the current one in the menu settings,
+// the countdown set longer in the config file is switched off
+func nextCountdownSetting(current int) int {
+	for idx, seconds := range countdownMenuSettings {
+		if seconds == current && idx+1 < len(countdownMenuSettings) {
+			return countdownMenuSettings[idx+1]
+		}
+	}
+	return countdownMenuSettings[0]
+}
+
+// countdownSetting describes the configured countdown for the Settings menu,
+// all of the settings are equally long, so the menu fitting one of them fits the others
+func countdownSetting() string {
+	if gameConfig.Countdown <= 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%d s", minInt(gameConfig.Countdown, maxCountdown))
+}
+
+const (
+	countdownWindowHeight = 5
+	countdownWindowWidth  = 7
+)
+
+// countdownDigits are the big digits shown by the countdown, 3 lines each
+var countdownDigits = [][]string{
+	{" _ ", "| |", "|_|"},
+	{"   ", "  |", "  |"},
+	{" _ ", " _|", "|_ "},
+	{" _ ", " _|", " _|"},
+	{"   ", "|_|", "  |"},
+	{" _ ", "|_ ", " _|"},
+	{" _ ", "|_ ", "|_|"},
+	{" _ ", "  |", "  |"},
+	{" _ ", "|_|", "|_|"},
+	{" _ ", "|_|", " _|"}}
+
+// countdownOverlay shows the seconds left until the game continues in the small window over the game field
+type countdownOverlay struct {
+	remainingTicks int
+	window         *gc.Window
+}
+
+// Start shows the countdown for the specified amount of seconds. Non-positive amount disables the countdown
+func (countdown *countdownOverlay) Start(parent *gc.Window, seconds int) {
+	countdown.Stop(parent)
+	if seconds <= 0 {
+		return
+	}
+	if seconds > maxCountdown {
+		seconds = maxCountdown
+	}
+
+	parentY, parentX := parent.YX()
+	lines, cols := parent.MaxYX()
+	wnd, windowCreateError := createWindow(
+		countdownWindowHeight,
+		countdownWindowWidth,
+		parentY+(lines-countdownWindowHeight)/2,
+		parentX+(cols-countdownWindowWidth)/2)
+	if windowCreateError != nil {
+		log.Println("Error creating countdown window: ", windowCreateError)
+		return
+	}
+
+	countdown.window = wnd
+	countdown.remainingTicks = seconds * speedFactor
+}
+
+// Active checks if the countdown is still in progress
+func (countdown *countdownOverlay) Active() bool {
+	return countdown.remainingTicks > 0
+}
+
+// Update advances the countdown by one tick, hiding it once the time is up
+func (countdown *countdownOverlay) Update(parent *gc.Window) {
+	countdown.remainingTicks--
+	if countdown.remainingTicks <= 0 {
+		countdown.Stop(parent)
+	}
+}
+
+// Stop hides the countdown
+func (countdown *countdownOverlay) Stop(parent *gc.Window) {
+	countdown.remainingTicks = 0
+	if countdown.window != nil {
+		removeWindow(countdown.window)
+		countdown.window = nil
+		parent.Touch()
+	}
+}
+
+// Draw renders the seconds left without updating the physical screen
+func (countdown *countdownOverlay) Draw() {
+	if countdown.window == nil {
+		return
+	}
+
+	seconds := (countdown.remainingTicks + speedFactor - 1) / speedFactor
+	wnd := countdown.window
+	wnd.Erase()
+	wnd.Box(0, 0)
+	wnd.ColorOn(textColorPair)
+	wnd.AttrOn(gc.A_BOLD)
+	for idx, line := range countdownDigits[seconds] {
+		wnd.MovePrint(1+idx, 2, line)
+	}
+	wnd.AttrOff(gc.A_BOLD)
+	wnd.ColorOff(textColorPair)
+	wnd.NoutRefresh()
+}
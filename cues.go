@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// dangerCueDistance is the amount of tiles ahead of the snake checked for the walls and the body
+const dangerCueDistance = 3
+
+// cueStream describes the game with short text lines, like "food 3 up 5 left", for the screen readers.
+// The lines are written to the cue file configured in the settings, the danger can also be signalled with the bell
+type cueStream struct {
+	output  io.Writer
+	audible bool
+	// danger is the last announced obstacle ahead of the snake, empty if the way is clear
+	danger string
+	// finished is set once the game over is announced
+	finished bool
+}
+
+// initCues opens the cue file and starts passing the game events to the cue stream. Returns nil if the cues are off
+func initCues() *os.File {
+	if gameConfig.CueFile == "" {
+		return nil
+	}
+
+	cueFile, openError := os.OpenFile(gameConfig.CueFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if openError != nil {
+		log.Println("Error opening cue file, cues are disabled: ", openError)
+		return nil
+	}
+	eventListeners = append(eventListeners, &cueStream{output: cueFile, audible: gameConfig.AudibleCues})
+	log.Printf("Cues are written to %s", gameConfig.CueFile)
+	return cueFile
+}
+
+func (cues *cueStream) handleGameEvent(event gameEvent) {
+	switch event.name {
+	case gameStartedEvent:
+		cues.danger, cues.finished = "", false
+		cues.say("game started, heading %s, %s", directionName(playerSnake.direction), foodCue())
+	case turnEvent:
+		cues.say("%s, %s", directionName(playerSnake.direction), foodCue())
+	case foodEatenEvent:
+		cues.say("ate %s food, score %d, %s", event.foodKind, score, foodCue())
+	case tickEvent:
+		cues.warnDanger()
+	}
+
+	if deathCause != "" && deathCause != quitDeathCause && !cues.finished {
+		cues.finished = true
+		cues.say("game over, %s, score %d", strings.ToLower(deathCauseDescriptions[deathCause]), score)
+		cues.beep()
+	}
+}
+
+// warnDanger announces the wall or the body ahead of the snake once it appears or gets closer
+func (cues *cueStream) warnDanger() {
+	danger := dangerAhead(playerSnake)
+	if danger != "" && danger != cues.danger {
+		cues.say("%s", danger)
+		cues.beep()
+	}
+	cues.danger = danger
+}
+
+func (cues *cueStream) say(format string, args ...interface{}) {
+	if _, writeError := fmt.Fprintf(cues.output, format+"\n", args...); writeError != nil {
+		log.Println("Error writing cue: ", writeError)
+	}
+}
+
+func (cues *cueStream) beep() {
+	if cues.audible {
+		gc.Beep()
+	}
+}
+
+// foodCue describes the position of the food relative to the snake head, like "food 3 up 5 left"
+func foodCue() string {
+	head := playerSnake.head.Data.(point)
+	parts := []string{"food"}
+	if dy := currentFood.position.y - head.y; dy != 0 {
+		parts = append(parts, fmt.Sprint(absInt(dy)), directionName(verticalDirection(dy)))
+	}
+	if dx := currentFood.position.x - head.x; dx != 0 {
+		parts = append(parts, fmt.Sprint(absInt(dx)), directionName(horizontalDirection(dx)))
+	}
+	if currentFood.kind == bonusFoodKind {
+		parts[0] = "bonus food"
+	}
+	return strings.Join(parts, " ")
+}
+
+// dangerAhead describes the closest wall or body segment in the moving direction, like "wall 2 ahead",
+// empty string if there is none within dangerCueDistance tiles
+func dangerAhead(s *snake) string {
+	position := s.head.Data.(point)
+	for distance := 1; distance <= dangerCueDistance; distance++ {
+		position = point{position.y + s.direction.y, position.x + s.direction.x}
+		switch s.collisionCause(&Node{Data: position}) {
+		case wallDeathCause:
+			return fmt.Sprintf("wall %d ahead", distance)
+		case selfDeathCause:
+			return fmt.Sprintf("tail %d ahead", distance)
+		}
+	}
+	return ""
+}
+
+func verticalDirection(dy int) *point {
+	if dy < 0 {
+		return up
+	}
+	return down
+}
+
+func horizontalDirection(dx int) *point {
+	if dx < 0 {
+		return left
+	}
+	return right
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	gc "github.com/rthornton128/goncurses"
+)
+
+const demoLabel = " DEMO - press any key "
+
+// demoScene plays the game controlled by the computer until any key is pressed
+type demoScene struct {
+	window *gc.Window
+}
+
+func newDemoScene(window *gc.Window) *demoScene {
+	return &demoScene{window: window}
+}
+
+func (scene *demoScene) Name() string { return "demo" }
+
+func (scene *demoScene) Enter() {
+	newGame(scene.window, maxY/2, maxX/2)
+}
+
+func (scene *demoScene) Leave() {
+	scorePopups.Clear(scene.window)
+	effects.Clear(scene.window)
+}
+
+func (scene *demoScene) HandleKey(key gc.Key) SceneTransition {
+	return replaceScene(newTitleScene(scene.window))
+}
+
+func (scene *demoScene) Update() SceneTransition {
+	scene.UpdateBackground()
+	return stay()
+}
+
+// UpdateBackground plays the demo game, restarting it once it is over
+func (scene *demoScene) UpdateBackground() {
+	playerSnake.direction = demoDirection(playerSnake, currentFood.position)
+	tick(scene.window)
+	drawStats(playerSnake)
+	if scene.handleEvents() {
+		newGame(scene.window, maxY/2, maxX/2)
+	}
+	scorePopups.Update(scene.window)
+}
+
+// handleEvents processes the events of the demo game, which are not passed to the event listeners,
+// so the demo does not unlock achievements. Returns true if the demo game is over
+func (scene *demoScene) handleEvents() bool {
+	isOver := false
+	for {
+		select {
+		case event := <-events:
+			switch event.name {
+			case foodEatenEvent:
+				incrementScore(event)
+			case collisionEvent:
+				isOver = true
+			case tickEvent:
+				if timeLimit := scoring.TimeLimit(); timeLimit > 0 && event.tick >= timeLimit {
+					isOver = true
+				}
+			}
+		default:
+			return isOver
+		}
+	}
+}
+
+func (scene *demoScene) Draw() {
+	_, cols := scene.window.MaxYX()
+	scene.window.ColorOn(textColorPair)
+	scene.window.MovePrint(0, (cols-len(demoLabel))/2, demoLabel)
+	scene.window.ColorOff(textColorPair)
+	scene.window.Touch()
+	scene.window.NoutRefresh()
+	if statsWindow != nil {
+		statsWindow.Touch()
+		statsWindow.NoutRefresh()
+	}
+	scorePopups.Draw()
+}
+
+// demoDirection chooses the direction of the computer controlled snake: the safe one which brings it
+// closer to the target, preferring the current direction. The current direction is kept if nothing is safe
+func demoDirection(s *snake, target *point) *point {
+	head := s.head.Data.(point)
+	best := s.direction
+	bestDistance := -1
+	for _, direction := range []*point{s.direction, up, down, left, right} {
+		if direction.y == -s.direction.y && direction.x == -s.direction.x {
+			continue
+		}
+
+		next := point{head.y + direction.y, head.x + direction.x}
+		if s.collisionCause(&Node{Data: next}) != "" {
+			continue
+		}
+
+		distance := absInt(target.y-next.y) + absInt(target.x-next.x)
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = direction, distance
+		}
+	}
+	return best
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+const (
+	// eatBurstDuration is the amount of ticks the particles fly away from the eaten food
+	eatBurstDuration = 3 * speedFactor / 4
+	// eatBurstDistance is the amount of rows the particles fly, they fly twice as many columns to look round
+	eatBurstDistance = 2
+	// deathExplosionDelay is the amount of ticks between the explosions of the neighbouring segments
+	deathExplosionDelay = 1
+	// deathExplosionFrameDuration is the amount of ticks every frame of the segment explosion is shown
+	deathExplosionFrameDuration = 2
+	// scorePopupFloatDuration is the amount of ticks the awarded points float up from the eaten food
+	scorePopupFloatDuration = 2 * speedFactor
+	// scorePopupRise is the amount of rows the awarded points float up
+	scorePopupRise = 3
+)
+
+var eatBurstFrames = []string{`*`, `+`, `.`}
+var deathExplosionFrames = []string{`@`, `*`, `#`, `+`, `.`}
+
+// effect is the animation bound to the position on the game field, optionally moving away from it
+type effect struct {
+	position  point
+	animation Animation
+	// offset is the distance the effect moves by the end of the animation, easing defines the speed of the movement
+	offset point
+	easing Easing
+	// delay is the amount of ticks before the effect appears
+	delay     int
+	color     int16
+	attribute gc.Char
+	// drawnAt and drawnWidth are the cells of the last drawn frame, erased before the next one is drawn
+	drawnAt    *point
+	drawnWidth int
+}
+
+// effectLayer plays the effects on the game field. The effects are drawn under the game objects
+type effectLayer struct {
+	effects []*effect
+}
+
+var effects = &effectLayer{}
+
+// Add starts playing the effects
+func (layer *effectLayer) Add(added ...*effect) {
+	layer.effects = append(layer.effects, added...)
+}
+
+// Update erases the previous frames of the effects and draws the current ones, advancing the animations.
+// The finished effects are removed with their last frame erased
+func (layer *effectLayer) Update(w *gc.Window) {
+	playing := layer.effects[:0]
+	for _, current := range layer.effects {
+		current.erase(w)
+		switch {
+		case current.delay > 0:
+			current.delay--
+		case current.animation.Finished():
+			continue
+		default:
+			current.draw(w)
+			current.animation.MoveFrameIndex()
+		}
+		playing = append(playing, current)
+	}
+	layer.effects = playing
+}
+
+// Clear erases and removes all of the effects
+func (layer *effectLayer) Clear(w *gc.Window) {
+	for _, current := range layer.effects {
+		current.erase(w)
+	}
+	layer.effects = nil
+}
+
+// currentPosition is the position moved by the share of the offset at the progress of the animation
+func (e *effect) currentPosition() point {
+	if e.easing == nil {
+		return e.position
+	}
+	shift := e.easing(e.animation.Progress())
+	return point{
+		e.position.y + int(math.Round(float64(e.offset.y)*shift)),
+		e.position.x + int(math.Round(float64(e.offset.x)*shift))}
+}
+
+// draw prints the current frame. The glyphs on the walls are skipped, the longer frames crossing them are moved inside
+func (e *effect) draw(w *gc.Window) {
+	frame := e.animation.CurrentFrame()
+	width := len([]rune(frame))
+	lines, cols := w.MaxYX()
+	position := e.currentPosition()
+	if width == 0 || position.y < 1 || position.y > lines-2 || width > cols-2 {
+		return
+	}
+	if width == 1 && (position.x < 1 || position.x > cols-2) {
+		return
+	}
+	position.x = minInt(maxInt(position.x, 1), cols-1-width)
+
+	w.ColorOn(e.color)
+	w.AttrOn(e.attribute)
+	w.MovePrint(position.y, position.x, frame)
+	w.AttrOff(e.attribute)
+	w.ColorOff(e.color)
+	e.drawnAt, e.drawnWidth = &position, width
+}
+
+func (e *effect) erase(w *gc.Window) {
+	if e.drawnAt != nil {
+		w.MovePrint(e.drawnAt.y, e.drawnAt.x, strings.Repeat(emptyTexture, e.drawnWidth))
+		e.drawnAt = nil
+	}
+}
+
+// newEatBurst creates the particles flying in all directions from the eaten food
+func newEatBurst(center point, color int16) []*effect {
+	particles := []*effect{}
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dy == 0 && dx == 0 {
+				continue
+			}
+			particles = append(particles, &effect{
+				position:  center,
+				animation: NewEasedAnimation(eatBurstFrames, eatBurstDuration, easeIn),
+				offset:    point{dy * eatBurstDistance, dx * eatBurstDistance * 2},
+				easing:    easeOut,
+				color:     color})
+		}
+	}
+	return particles
+}
+
+// newDeathExplosion creates the explosions running along the snake body from the head to the tail.
+// The exploded segments are erased, so the snake disappears
+func newDeathExplosion(s *snake) []*effect {
+	explosions := []*effect{}
+	for idx, segment := range s.segments() {
+		explosions = append(explosions, &effect{
+			position:  segment,
+			animation: NewOneShotAnimation(deathExplosionFrames, deathExplosionFrameDuration),
+			delay:     idx * deathExplosionDelay,
+			color:     foodColorPair,
+			attribute: gc.A_BOLD})
+	}
+	return explosions
+}
+
+// newScorePopup creates the awarded points floating up from the eaten food
+func newScorePopup(position point, points int) *effect {
+	text := fmt.Sprintf("+%d", points)
+	return &effect{
+		position:  point{position.y, position.x - len(text)/2},
+		animation: NewOneShotAnimation([]string{text}, scorePopupFloatDuration),
+		offset:    point{-scorePopupRise, 0},
+		easing:    easeOut,
+		color:     textColorPair,
+		attribute: gc.A_BOLD}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// fileLock is an advisory lock held on a dedicated lock file.
+// It only protects against other cooperating GSnake processes.
+type fileLock struct {
+	file *os.File
+}
+
+// acquireFileLock blocks until the lock on the specified file is obtained.
+// Shared locks may be held by several readers at once, exclusive lock - only by a single writer
+func acquireFileLock(path string, exclusive bool) (*fileLock, error) {
+	file, openError := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	if openError != nil {
+		return nil, openError
+	}
+
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+
+	lockError := syscall.Flock(int(file.Fd()), how)
+	for lockError == syscall.EINTR {
+		lockError = syscall.Flock(int(file.Fd()), how)
+	}
+	if lockError != nil {
+		file.Close()
+		return nil, lockError
+	}
+
+	return &fileLock{file: file}, nil
+}
+
+func (lock *fileLock) release() {
+	syscall.Flock(int(lock.file.Fd()), syscall.LOCK_UN)
+	lock.file.Close()
+}
+
+// writeFileAtomic writes the data to a temporary file next to the target, flushes it to disk
+// and renames it over the target, so readers observe either the old or the new content, never a partial one.
+// If backupPath is not empty, the current target file is moved there before being replaced.
+func writeFileAtomic(path string, data []byte, backupPath string) error {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tempFile, createError := ioutil.TempFile(dir, base+".tmp")
+	if createError != nil {
+		return createError
+	}
+	tempName := tempFile.Name()
+	defer os.Remove(tempName) // no-op once the file is renamed
+
+	if _, writeError := tempFile.Write(data); writeError != nil {
+		tempFile.Close()
+		return writeError
+	}
+	if syncError := tempFile.Sync(); syncError != nil {
+		tempFile.Close()
+		return syncError
+	}
+	if closeError := tempFile.Close(); closeError != nil {
+		return closeError
+	}
+	if chmodError := os.Chmod(tempName, 0644); chmodError != nil {
+		return chmodError
+	}
+
+	if backupPath != "" {
+		backupError := os.Rename(path, backupPath)
+		if backupError != nil && !os.IsNotExist(backupError) {
+			return backupError
+		}
+	}
+
+	if renameError := os.Rename(tempName, path); renameError != nil {
+		return renameError
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir flushes the directory entry changes (e.g. renames) to disk
+func syncDir(dir string) error {
+	dirFile, openError := os.Open(dir)
+	if openError != nil {
+		return openError
+	}
+	defer dirFile.Close()
+	return dirFile.Sync()
+}
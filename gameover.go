@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+
+	"github.com/VAlux/GSnake/widget"
+	gc "github.com/rthornton128/goncurses"
+)
+
+const (
+	gameOverWindowTitle  = "Game Over"
+	gameOverWindowWidth  = 50
+	gameOverWindowHeight = 14
+)
+
+// GameOverAction is the option chosen in the game over window
+type GameOverAction int
+
+// Options of the game over window. Saving the replay does not close the window
+const (
+	retryGameOverAction GameOverAction = iota
+	saveReplayGameOverAction
+	mainMenuGameOverAction
+	quitGameOverAction
+)
+
+var gameOverActionTitles = []string{"Retry", "Save replay", "Main menu", "Quit"}
+
+var deathCauseDescriptions = map[string]string{
+	wallDeathCause: "Crashed into the wall",
+	selfDeathCause: "Bit its own tail",
+	quitDeathCause: "Quit the game",
+	timeDeathCause: "Time is up"}
+
+// GameOverWindow shows the summary of the finished game and lets the player decide what to do next
+type GameOverWindow struct {
+	result HighScore
+	rank   int
+	replay *Replay
+	dialog *widget.Dialog
+	status *widget.Label
+	// chosen is the action of the last pressed button
+	chosen GameOverAction
+	// pressed is set once any of the buttons is pressed
+	pressed bool
+}
+
+// NewGameOverWindow creates the game over window for the game result. Rank is the position of the result
+// among the high scores of the same mode, 0 if the result is not in the high scores
+func NewGameOverWindow(result HighScore, rank int, replay *Replay) *GameOverWindow {
+	return &GameOverWindow{result: result, rank: rank, replay: replay}
+}
+
+// Open creates the window as a child of specified window
+func (gameOverWindow *GameOverWindow) Open(s *gc.Window) error {
+	buttons := []widget.Widget{}
+	for idx, title := range gameOverActionTitles {
+		action := GameOverAction(idx)
+		buttons = append(buttons, widget.NewButton(title, func() {
+			gameOverWindow.chosen, gameOverWindow.pressed = action, true
+		}))
+	}
+	gameOverWindow.status = widget.NewLabel("")
+	gameOverWindow.status.Color = 0
+
+	summary := widget.NewLabel(gameOverWindow.lines()...)
+	summary.Fill = true
+	content := widget.NewColumn(summary, gameOverWindow.status, widget.NewSpacer(1), widget.NewRow(buttons...))
+
+	dialog, dialogError := widget.NewDialog(s, gameOverWindowHeight, gameOverWindowWidth, gameOverWindowTitle, content)
+	if dialogError != nil {
+		return dialogError
+	}
+	gameOverWindow.dialog = dialog
+	return nil
+}
+
+// Close removes the window from the screen
+func (gameOverWindow *GameOverWindow) Close() {
+	gameOverWindow.dialog.Close()
+}
+
+// HandleKey applies the key action. Returns the chosen action and true once the option is activated
+func (gameOverWindow *GameOverWindow) HandleKey(key gc.Key) (GameOverAction, bool) {
+	switch key {
+	case gc.KEY_LEFT, gc.KEY_UP:
+		key = gc.KEY_BTAB
+	case gc.KEY_RIGHT, gc.KEY_DOWN:
+		key = gc.KEY_TAB
+	case 'r':
+		return retryGameOverAction, true
+	case escapeKey:
+		return mainMenuGameOverAction, true
+	case 'q':
+		return quitGameOverAction, true
+	}
+
+	gameOverWindow.pressed = false
+	gameOverWindow.dialog.HandleKey(key)
+	return gameOverWindow.chosen, gameOverWindow.pressed
+}
+
+func (gameOverWindow *GameOverWindow) saveReplay() {
+	if gameOverWindow.replay == nil {
+		gameOverWindow.status.SetText("No replay recorded")
+		return
+	}
+
+	path, saveError := SaveReplay(filepath.Join(dataDir(), replayDirName), &gameOverWindow.result, gameOverWindow.replay)
+	if saveError != nil {
+		log.Println("Error saving replay: ", saveError)
+		gameOverWindow.status.SetText("Error saving replay")
+		return
+	}
+	log.Printf("Replay saved: %s", path)
+	gameOverWindow.status.SetText("Saved " + filepath.Base(path))
+}
+
+// lines returns the summary of the game
+func (gameOverWindow *GameOverWindow) lines() []string {
+	result := gameOverWindow.result
+	cause, known := deathCauseDescriptions[result.DeathCause]
+	if !known {
+		cause = optionalText(result.DeathCause)
+	}
+	rank := "-"
+	if gameOverWindow.rank > 0 {
+		rank = "#" + strconv.Itoa(gameOverWindow.rank)
+	}
+
+	return []string{
+		cause,
+		"",
+		fmt.Sprintf("Score:    %d", result.Score),
+		fmt.Sprintf("Length:   %d", result.Length),
+		fmt.Sprintf("Duration: %s", formatDuration(result.Duration)),
+		fmt.Sprintf("Rank:     %s", rank)}
+}
+
+func (gameOverWindow *GameOverWindow) draw() {
+	gameOverWindow.dialog.Draw()
+}
+
+// gameOverScene shows the game over window over the finished game
+type gameOverScene struct {
+	parent *gc.Window
+	window *GameOverWindow
+	opened bool
+}
+
+func newGameOverScene(parent *gc.Window, result HighScore) *gameOverScene {
+	return &gameOverScene{
+		parent: parent,
+		window: NewGameOverWindow(result, scoreRank(scoreStore, result), currentReplay)}
+}
+
+func (scene *gameOverScene) Name() string { return "gameOver" }
+
+func (scene *gameOverScene) Enter() {
+	toasts.Clear(scene.parent)
+	scorePopups.Clear(scene.parent)
+	if openError := scene.window.Open(scene.parent); openError != nil {
+		log.Println("Error creating game over window: ", openError)
+		return
+	}
+	scene.opened = true
+}
+
+func (scene *gameOverScene) Leave() {
+	if scene.opened {
+		scene.window.Close()
+	}
+}
+
+// HandleKey performs the chosen action. Saving the replay does not close the window
+func (scene *gameOverScene) HandleKey(key gc.Key) SceneTransition {
+	action, chosen := scene.window.HandleKey(key)
+	if !chosen {
+		return stay()
+	}
+
+	switch action {
+	case retryGameOverAction:
+		newGame(scene.parent, maxY/2, maxX/2)
+		return popScene()
+	case saveReplayGameOverAction:
+		scene.window.saveReplay()
+		return stay()
+	case mainMenuGameOverAction:
+		// the finished game is closed too, the next one is started from the title screen
+		return resetScenes(newTitleScene(scene.parent))
+	}
+	return quitGame()
+}
+
+func (scene *gameOverScene) Update() SceneTransition {
+	if !scene.opened {
+		return quitGame()
+	}
+	return stay()
+}
+
+func (scene *gameOverScene) Draw() {
+	if scene.opened {
+		scene.window.draw()
+	}
+}
+
+// scoreRank returns the position of the result among the high scores of its mode, 0 if it is not there
+func scoreRank(store ScoreStore, result HighScore) int {
+	if result.Score <= 0 {
+		return 0
+	}
+
+	scores, loadError := store.Top(0, ScoreFilter{Mode: result.Mode})
+	if loadError != nil {
+		log.Println("Error loading high scores: ", loadError)
+		return 0
+	}
+
+	rank := 1
+	for _, stored := range scores {
+		if stored.Score > result.Score {
+			rank++
+		}
+	}
+	return rank
+}
@@ -5,8 +5,10 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/gob"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
@@ -16,17 +18,24 @@ import (
 )
 
 const key = "cegthctrm.hysqrk.xrjnjhsqytdjpvj"
-const highScoreFilename = "score.hsc"
-const highscoreWindowTitle = "High scores"
-const highScoreWindowWidth = 70
-const highScoreWindowHeight = 14
-const maxAmountOfTopHighScores = 10
 
-// HighScore represents all of the single high-score entry components
+// HighScore represents all of the single high-score entry components.
+// Fields added after the first version are zero in the entries decoded from the older files
 type HighScore struct {
-	Timestamp  t.Time
-	Score      int
-	PlayerName string
+	Timestamp   t.Time         `json:"timestamp"`
+	Score       int            `json:"score"`
+	PlayerName  string         `json:"playerName"`
+	Length      int            `json:"length,omitempty"`
+	Duration    t.Duration     `json:"duration,omitempty"`
+	Ticks       int            `json:"ticks,omitempty"`
+	FoodEaten   map[string]int `json:"foodEaten,omitempty"`
+	BoardWidth  int            `json:"boardWidth,omitempty"`
+	BoardHeight int            `json:"boardHeight,omitempty"`
+	Mode        string         `json:"mode,omitempty"`
+	Difficulty  string         `json:"difficulty,omitempty"`
+	Seed        int64          `json:"seed,omitempty"`
+	Version     string         `json:"version,omitempty"`
+	DeathCause  string         `json:"deathCause,omitempty"`
 }
 
 // HighScores represents a slice of HighScore entries
@@ -42,6 +51,70 @@ func (score *HighScore) String() string {
 		strconv.Itoa(score.Score)
 }
 
+const highScoreTableRowFormat = "%-16s %-12.12s %6v %6s %6s %5s %s"
+
+// HighScoreTableHeader returns the header of the high score table with the columns of TableRow
+func HighScoreTableHeader() string {
+	return fmt.Sprintf(highScoreTableRowFormat, "Date", "Player", "Score", "Length", "Time", "Food", "Mode")
+}
+
+// TableRow formats the high score entry as the row of the high score table.
+// Unknown values of the entries from the older versions are shown as '-'
+func (score *HighScore) TableRow() string {
+	return fmt.Sprintf(highScoreTableRowFormat,
+		score.Timestamp.Format("02 Jan 06 15:04"),
+		score.PlayerName,
+		score.Score,
+		optionalNumber(score.Length),
+		formatDuration(score.Duration),
+		optionalNumber(score.TotalFoodEaten()),
+		optionalText(score.Mode))
+}
+
+// TableCells returns the values of the high score table columns of the entry
+func (score *HighScore) TableCells() []string {
+	return []string{
+		score.Timestamp.Format("02 Jan 06 15:04"),
+		score.PlayerName,
+		strconv.Itoa(score.Score),
+		optionalNumber(score.Length),
+		formatDuration(score.Duration),
+		optionalNumber(score.TotalFoodEaten()),
+		optionalText(score.Mode)}
+}
+
+// TotalFoodEaten sums the food eaten of all types
+func (score *HighScore) TotalFoodEaten() int {
+	total := 0
+	for _, amount := range score.FoodEaten {
+		total += amount
+	}
+	return total
+}
+
+func optionalNumber(value int) string {
+	if value == 0 {
+		return "-"
+	}
+	return strconv.Itoa(value)
+}
+
+func optionalText(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// formatDuration formats the duration as minutes and seconds
+func formatDuration(duration t.Duration) string {
+	if duration <= 0 {
+		return "-"
+	}
+	seconds := int(duration / t.Second)
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
 func (scores *HighScores) String() string {
 	content := ""
 	for _, score := range *scores {
@@ -50,79 +123,126 @@ func (scores *HighScores) String() string {
 	return content
 }
 
-func serialize(scores *HighScores) ([]byte, error) {
+// highScoreFileMagic marks the append-only high score file format.
+// Files without it contain the single encrypted gob of the whole HighScores slice, written by older versions.
+const highScoreFileMagic = "GSHS2\n"
+
+// highScoreRecordHeaderSize is the size of the record length prefix
+const highScoreRecordHeaderSize = 4
+
+// serializeRecord encodes and encrypts the single high score as the length-prefixed record
+func serializeRecord(score *HighScore) ([]byte, error) {
 	buffer := bytes.Buffer{}
 	encoder := gob.NewEncoder(&buffer)
-	err := encoder.Encode(*scores)
+	err := encoder.Encode(*score)
 	if err != nil {
-		log.Panic("Error serialization high score:", err)
+		log.Println("Error serialization high score:", err)
 		return nil, err
 	}
-	return buffer.Bytes(), nil
+
+	encrypted, encryptionError := encrypt(buffer.Bytes())
+	if encryptionError != nil {
+		return nil, encryptionError
+	}
+
+	record := make([]byte, highScoreRecordHeaderSize, highScoreRecordHeaderSize+len(encrypted))
+	binary.BigEndian.PutUint32(record, uint32(len(encrypted)))
+	return append(record, encrypted...), nil
+}
+
+// serialize encodes the whole high score file content
+func serialize(scores HighScores) ([]byte, error) {
+	content := []byte(highScoreFileMagic)
+	for idx := range scores {
+		record, err := serializeRecord(&scores[idx])
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, record...)
+	}
+	return content, nil
+}
+
+// completeRecordsSize returns the size of the append-only file content up to the end of its last complete record.
+// The crash during the append leaves the partial record after it
+func completeRecordsSize(content []byte) int {
+	size := len(highScoreFileMagic)
+	for len(content)-size >= highScoreRecordHeaderSize {
+		recordSize := int(binary.BigEndian.Uint32(content[size:]))
+		if recordSize > len(content)-size-highScoreRecordHeaderSize {
+			break
+		}
+		size += highScoreRecordHeaderSize + recordSize
+	}
+	return size
 }
 
 func deSerialize(file *os.File) (*HighScores, error) {
 	content, readingError := ioutil.ReadAll(file)
 	if readingError != nil {
-		log.Panic("Error reading contents from file:", readingError)
+		log.Println("Error reading contents from file:", readingError)
 		return nil, readingError
 	}
 
-	decrypted, decryptionError := decrypt(content)
+	if !bytes.HasPrefix(content, []byte(highScoreFileMagic)) {
+		return deSerializeLegacy(content)
+	}
+
+	scores := HighScores{}
+	content = content[len(highScoreFileMagic):]
+	for len(content) > 0 {
+		if len(content) < highScoreRecordHeaderSize {
+			log.Println("Ignoring incomplete high score record header at the end of file")
+			break
+		}
+		recordSize := int(binary.BigEndian.Uint32(content))
+		content = content[highScoreRecordHeaderSize:]
+		if recordSize > len(content) {
+			log.Println("Ignoring incomplete high score record at the end of file")
+			break
+		}
+
+		score, recordError := deSerializeRecord(content[:recordSize])
+		content = content[recordSize:]
+		if recordError != nil {
+			log.Println("Skipping damaged high score record:", recordError)
+			continue
+		}
+		scores = append(scores, *score)
+	}
+	return &scores, nil
+}
+
+func deSerializeRecord(record []byte) (*HighScore, error) {
+	decrypted, decryptionError := decrypt(record)
 	if decryptionError != nil {
-		log.Panic("Error decrypting high score contents:", decryptionError)
 		return nil, decryptionError
 	}
 
-	score := new(HighScores)
+	score := new(HighScore)
 	decoder := gob.NewDecoder(bytes.NewReader(decrypted))
 	err := decoder.Decode(score)
 	if err != nil {
-		log.Panic("Error de-serializing high score:", err)
 		return nil, err
 	}
 	return score, nil
 }
 
-// SaveHighScore writes high score structure to file
-func SaveHighScore(score *HighScore) {
-	currentScores, _ := LoadHighScore()
-	currentScores = append(currentScores, *score)
-
-	payload, serializeError := serialize(&currentScores)
-	if serializeError != nil {
-		log.Panic("Error saving high score to file:", serializeError)
-		return
-	}
-
-	encryptedPayload, encryptionError := encrypt(payload)
-	if encryptionError != nil {
-		log.Panic("Error encryption of the high score payload:", encryptionError)
-	}
-
-	saveError := ioutil.WriteFile(highScoreFilename, encryptedPayload, 0666)
-	if saveError != nil {
-		log.Panic("Error saving high score to file:", saveError)
-		return
-	}
-
-	log.Printf("High score successfully saved to file: %s", highScoreFilename)
-}
-
-// LoadHighScore reads high score structure from file
-func LoadHighScore() (HighScores, error) {
-	log.Printf("Loading high score from file: %s", highScoreFilename)
-	file, readError := os.Open(highScoreFilename)
-	if readError != nil {
-		return nil, readError
+func deSerializeLegacy(content []byte) (*HighScores, error) {
+	decrypted, decryptionError := decrypt(content)
+	if decryptionError != nil {
+		log.Println("Error decrypting high score contents:", decryptionError)
+		return nil, decryptionError
 	}
 
-	scores, deSerializeError := deSerialize(file)
-	if deSerializeError != nil {
-		return nil, readError
+	score := new(HighScores)
+	decoder := gob.NewDecoder(bytes.NewReader(decrypted))
+	err := decoder.Decode(score)
+	if err != nil {
+		log.Println("Error de-serializing high score:", err)
+		return nil, err
 	}
-
-	return *scores, nil
+	return score, nil
 }
 
 func encrypt(payload []byte) ([]byte, error) {
@@ -158,7 +278,7 @@ func decrypt(payload []byte) ([]byte, error) {
 
 	if len(payload) < aes.BlockSize {
 		errorMessage := "High score file is too short"
-		log.Panic("Decryption error:", errorMessage)
+		log.Println("Decryption error:", errorMessage)
 		return nil, errors.New(errorMessage)
 	}
 
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/VAlux/GSnake/widget"
+	gc "github.com/rthornton128/goncurses"
+)
+
+const (
+	highScoreBrowserTitle     = "High scores"
+	highScoreBrowserMaxWidth  = 80
+	highScoreBrowserMaxHeight = 24
+	highScoreBrowserHelp      = "Up/Dn PgUp/PgDn: scroll  s: sort  r: reverse  p: player  m: mode  q: close"
+	highScoreBrowserAllValues = "All"
+)
+
+// highScoreTableColumns are the columns of the high score table, the same as of HighScoreTableHeader
+var highScoreTableColumns = []widget.TableColumn{
+	{Title: "Date", Width: 16},
+	{Title: "Player", Width: 12},
+	{Title: "Score", Width: 6, AlignRight: true},
+	{Title: "Length", Width: 6, AlignRight: true},
+	{Title: "Time", Width: 6, AlignRight: true},
+	{Title: "Food", Width: 5, AlignRight: true},
+	{Title: "Mode"}}
+
+type highScoreSortKey int
+
+const (
+	sortByScore highScoreSortKey = iota
+	sortByDate
+	sortByLength
+	sortByDuration
+	highScoreSortKeysAmount
+)
+
+var highScoreSortKeyNames = map[highScoreSortKey]string{
+	sortByScore:    "score",
+	sortByDate:     "date",
+	sortByLength:   "length",
+	sortByDuration: "duration"}
+
+// HighScoreBrowser is an interactive table of the high scores with sorting, filtering and scrolling
+type HighScoreBrowser struct {
+	scores      HighScores
+	visible     []*HighScore
+	highlighted *HighScore
+
+	sortKey   highScoreSortKey
+	ascending bool
+
+	players     []string
+	playerIndex int
+	modes       []string
+	modeIndex   int
+
+	dialog *widget.Dialog
+	status *widget.Label
+	table  *widget.Table
+}
+
+// NewHighScoreBrowser creates the browser of the specified scores.
+// The most recent entry of the specified player is highlighted, or the most recent entry at all if the player is unknown
+func NewHighScoreBrowser(scores HighScores, playerName string) *HighScoreBrowser {
+	browser := &HighScoreBrowser{
+		scores:  scores,
+		sortKey: sortByScore,
+		players: distinctScoreValues(scores, func(score *HighScore) string { return score.PlayerName }),
+		modes:   distinctScoreValues(scores, scoreMode),
+		status:  widget.NewLabel(""),
+		table:   widget.NewTable(highScoreTableColumns...)}
+
+	for idx := range browser.scores {
+		score := &browser.scores[idx]
+		if playerName != "" && score.PlayerName != playerName {
+			continue
+		}
+		if browser.highlighted == nil || score.Timestamp.After(browser.highlighted.Timestamp) {
+			browser.highlighted = score
+		}
+	}
+
+	browser.refreshVisible()
+	return browser
+}
+
+// distinctScoreValues returns the sorted unique non-empty values of the scores prepended by the 'all values' option
+func distinctScoreValues(scores HighScores, value func(*HighScore) string) []string {
+	seen := make(map[string]bool)
+	values := []string{}
+	for idx := range scores {
+		current := value(&scores[idx])
+		if current != "" && !seen[current] {
+			seen[current] = true
+			values = append(values, current)
+		}
+	}
+	sort.Strings(values)
+	return append([]string{highScoreBrowserAllValues}, values...)
+}
+
+// Open creates the browser window as a child of specified window
+func (browser *HighScoreBrowser) Open(s *gc.Window) error {
+	browser.status.Color = 0
+	content := widget.NewColumn(browser.status, browser.table)
+	dialog, dialogError := widget.NewDialog(s, highScoreBrowserMaxHeight, highScoreBrowserMaxWidth, highScoreBrowserTitle, content)
+	if dialogError != nil {
+		return dialogError
+	}
+
+	dialog.SetFooter(highScoreBrowserHelp)
+	browser.dialog = dialog
+	browser.scrollToHighlighted()
+	return nil
+}
+
+// Close removes the browser window from the screen
+func (browser *HighScoreBrowser) Close() {
+	browser.dialog.Close()
+}
+
+// HandleKey applies the key action to the browser state. Returns false if the browser should be closed
+func (browser *HighScoreBrowser) HandleKey(key gc.Key) bool {
+	switch key {
+	case ' ':
+		browser.table.HandleKey(gc.KEY_PAGEDOWN)
+	case 's':
+		browser.sortKey = (browser.sortKey + 1) % highScoreSortKeysAmount
+		browser.ascending = false
+		browser.refreshVisible()
+	case 'r':
+		browser.ascending = !browser.ascending
+		browser.refreshVisible()
+	case 'p':
+		browser.playerIndex = (browser.playerIndex + 1) % len(browser.players)
+		browser.refreshVisible()
+	case 'm':
+		browser.modeIndex = (browser.modeIndex + 1) % len(browser.modes)
+		browser.refreshVisible()
+	case 'q', escapeKey, gc.KEY_RETURN:
+		return false
+	default:
+		browser.table.HandleKey(key)
+	}
+	return true
+}
+
+func (browser *HighScoreBrowser) scrollToHighlighted() {
+	if browser.table.Highlighted >= 0 {
+		browser.table.ScrollTo(browser.table.Highlighted)
+	}
+}
+
+// filter returns the score filter built from the current player and mode selection
+func (browser *HighScoreBrowser) filter() ScoreFilter {
+	filter := ScoreFilter{}
+	if browser.playerIndex > 0 {
+		filter.PlayerName = browser.players[browser.playerIndex]
+	}
+	if browser.modeIndex > 0 {
+		filter.Mode = browser.modes[browser.modeIndex]
+	}
+	return filter
+}
+
+// refreshVisible applies the filter and the sort order to the scores
+func (browser *HighScoreBrowser) refreshVisible() {
+	filter := browser.filter()
+	browser.visible = []*HighScore{}
+	for idx := range browser.scores {
+		if filter.Match(&browser.scores[idx]) {
+			browser.visible = append(browser.visible, &browser.scores[idx])
+		}
+	}
+
+	less := browser.lessFunction()
+	sort.SliceStable(browser.visible, func(i, j int) bool {
+		if browser.ascending {
+			return less(browser.visible[i], browser.visible[j])
+		}
+		return less(browser.visible[j], browser.visible[i])
+	})
+
+	rows := [][]string{}
+	browser.table.Highlighted = -1
+	for idx, score := range browser.visible {
+		rows = append(rows, score.TableCells())
+		if score == browser.highlighted {
+			browser.table.Highlighted = idx
+		}
+	}
+	browser.table.SetRows(rows)
+}
+
+func (browser *HighScoreBrowser) lessFunction() func(a, b *HighScore) bool {
+	switch browser.sortKey {
+	case sortByDate:
+		return func(a, b *HighScore) bool { return a.Timestamp.Before(b.Timestamp) }
+	case sortByLength:
+		return func(a, b *HighScore) bool { return a.Length < b.Length }
+	case sortByDuration:
+		return func(a, b *HighScore) bool { return a.Duration < b.Duration }
+	default:
+		return func(a, b *HighScore) bool { return a.Score < b.Score }
+	}
+}
+
+func (browser *HighScoreBrowser) draw() {
+	order := "desc"
+	if browser.ascending {
+		order = "asc"
+	}
+	browser.status.SetText(fmt.Sprintf("Sort: %s %s  Player: %s  Mode: %s  (%d entries)",
+		highScoreSortKeyNames[browser.sortKey],
+		order,
+		browser.players[browser.playerIndex],
+		browser.modes[browser.modeIndex],
+		len(browser.visible)))
+	browser.dialog.Draw()
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	t "time"
+)
+
+const (
+	leaderboardDirName         = "leaderboard"
+	leaderboardScoresPath      = "/api/scores"
+	leaderboardDefaultAddress  = ":8080"
+	leaderboardDefaultTopSize  = 50
+	leaderboardMaxRequestBytes = 1 << 20
+	// the timeouts keep the slow or stalled clients from holding the server connections
+	leaderboardReadHeaderTimeout = 5 * t.Second
+	leaderboardReadTimeout       = 10 * t.Second
+	leaderboardWriteTimeout      = 10 * t.Second
+)
+
+// ScoreSubmission is the score sent to the leaderboard server together with the replay of the game.
+// Signature is the hex encoded HMAC-SHA256 of the score and replay, made with the secret shared by the players
+type ScoreSubmission struct {
+	Score     HighScore `json:"score"`
+	Replay    *Replay   `json:"replay"`
+	Signature string    `json:"signature"`
+}
+
+// signedPayload returns the bytes covered by the submission signature
+func (submission *ScoreSubmission) signedPayload() ([]byte, error) {
+	return json.Marshal(struct {
+		Score  HighScore `json:"score"`
+		Replay *Replay   `json:"replay"`
+	}{submission.Score, submission.Replay})
+}
+
+func (submission *ScoreSubmission) computeSignature(secret string) (string, error) {
+	payload, marshalError := submission.signedPayload()
+	if marshalError != nil {
+		return "", marshalError
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Sign sets the signature of the submission using the shared secret
+func (submission *ScoreSubmission) Sign(secret string) error {
+	signature, signError := submission.computeSignature(secret)
+	submission.Signature = signature
+	return signError
+}
+
+// Verify checks the submission signature, the score entry and that the replay belongs to the game of the score
+func (submission *ScoreSubmission) Verify(secret string) error {
+	expected, signError := submission.computeSignature(secret)
+	if signError != nil {
+		return signError
+	}
+	if !hmac.Equal([]byte(expected), []byte(submission.Signature)) {
+		return errors.New("invalid submission signature")
+	}
+
+	score := &submission.Score
+	if score.PlayerName == "" || score.Score < 0 {
+		return errors.New("invalid score entry")
+	}
+	if score.Timestamp.After(t.Now().Add(t.Minute)) {
+		return errors.New("score timestamp is in the future")
+	}
+	if submission.Replay == nil {
+		return errors.New("submission has no replay")
+	}
+	return submission.Replay.Validate(score)
+}
+
+// leaderboardServer serves the score store as the JSON API and the HTML page
+type leaderboardServer struct {
+	store     ScoreStore
+	secret    string
+	replayDir string
+	// submissions serializes the duplicate check and the insert, so the same score sent twice at once is stored once
+	submissions sync.Mutex
+}
+
+// newLeaderboardHandler creates the HTTP handler of the leaderboard backed by the store
+func newLeaderboardHandler(store ScoreStore, secret string, replayDir string) http.Handler {
+	server := &leaderboardServer{store: store, secret: secret, replayDir: replayDir}
+	mux := http.NewServeMux()
+	mux.HandleFunc(leaderboardScoresPath, server.handleScores)
+	mux.HandleFunc("/", server.handlePage)
+	return mux
+}
+
+func (server *leaderboardServer) handleScores(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case http.MethodGet:
+		scores, queryError := server.queryScores(request)
+		if queryError != nil {
+			http.Error(writer, queryError.Error(), http.StatusBadRequest)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(scores)
+	case http.MethodPost:
+		server.handleSubmission(writer, request)
+	default:
+		writer.Header().Set("Allow", "GET, POST")
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (server *leaderboardServer) queryScores(request *http.Request) (HighScores, error) {
+	query := request.URL.Query()
+	amount := leaderboardDefaultTopSize
+	if value := query.Get("n"); value != "" {
+		var parseError error
+		if amount, parseError = strconv.Atoi(value); parseError != nil {
+			return nil, parseError
+		}
+	}
+
+	scores, loadError := server.store.Top(amount, ScoreFilter{PlayerName: query.Get("player"), Mode: query.Get("mode")})
+	if loadError != nil {
+		// the store file does not exist until the first submission
+		log.Println("Error loading leaderboard scores:", loadError)
+		return HighScores{}, nil
+	}
+	return scores, nil
+}
+
+func (server *leaderboardServer) handleSubmission(writer http.ResponseWriter, request *http.Request) {
+	submission := ScoreSubmission{}
+	body := http.MaxBytesReader(writer, request.Body, leaderboardMaxRequestBytes)
+	if decodeError := json.NewDecoder(body).Decode(&submission); decodeError != nil {
+		http.Error(writer, "malformed submission: "+decodeError.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if verifyError := submission.Verify(server.secret); verifyError != nil {
+		log.Printf("Rejected leaderboard submission of %s: %s", submission.Score.PlayerName, verifyError)
+		http.Error(writer, verifyError.Error(), http.StatusForbidden)
+		return
+	}
+
+	added, addError := server.addNewScore(submission.Score)
+	if addError != nil {
+		http.Error(writer, "error saving score", http.StatusInternalServerError)
+		return
+	}
+	if !added {
+		writer.WriteHeader(http.StatusOK)
+		return
+	}
+	if _, replayError := SaveReplay(server.replayDir, &submission.Score, submission.Replay); replayError != nil {
+		log.Println("Error saving submitted replay:", replayError)
+	}
+
+	log.Printf("Leaderboard score accepted: %s %d", submission.Score.PlayerName, submission.Score.Score)
+	writer.WriteHeader(http.StatusCreated)
+}
+
+// addNewScore stores the score unless it is already stored. Returns whether the score is added
+func (server *leaderboardServer) addNewScore(score HighScore) (bool, error) {
+	server.submissions.Lock()
+	defer server.submissions.Unlock()
+
+	existing, _ := server.store.ForPlayer(score.PlayerName)
+	if len(newScoresOnly(existing, HighScores{score})) == 0 {
+		return false, nil
+	}
+	return true, server.store.Add(score)
+}
+
+var leaderboardPageTemplate = template.Must(template.New("leaderboard").Funcs(template.FuncMap{
+	"inc":      func(idx int) int { return idx + 1 },
+	"duration": formatDuration}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="30">
+<title>GSnake leaderboard</title>
+<style>
+body { font-family: monospace; background: #111; color: #ddd; }
+table { border-collapse: collapse; margin: 2em auto; }
+th, td { padding: 0.3em 1em; text-align: right; }
+th { color: #e5c07b; border-bottom: 1px solid #555; }
+td.player { text-align: left; color: #98c379; }
+</style>
+</head>
+<body>
+<h1 style="text-align: center">GSnake leaderboard</h1>
+<table>
+<tr><th>#</th><th>Player</th><th>Score</th><th>Length</th><th>Time</th><th>Mode</th><th>Date</th></tr>
+{{range $idx, $score := .}}<tr><td>{{inc $idx}}</td><td class="player">{{$score.PlayerName}}</td><td>{{$score.Score}}</td><td>{{$score.Length}}</td><td>{{duration $score.Duration}}</td><td>{{$score.Mode}}</td><td>{{$score.Timestamp.Format "2006-01-02 15:04"}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func (server *leaderboardServer) handlePage(writer http.ResponseWriter, request *http.Request) {
+	if request.URL.Path != "/" {
+		http.NotFound(writer, request)
+		return
+	}
+
+	scores, queryError := server.queryScores(request)
+	if queryError != nil {
+		http.Error(writer, queryError.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if renderError := leaderboardPageTemplate.Execute(writer, scores); renderError != nil {
+		log.Println("Error rendering leaderboard page:", renderError)
+	}
+}
+
+func leaderboardServeCommand(args []string, stdout io.Writer) error {
+	flags := newFlagSet("leaderboard serve")
+	address := flags.String("addr", leaderboardDefaultAddress, "address to listen on")
+	secret := flags.String("secret", gameConfig.LeaderboardSecret, "secret shared with the players to sign submissions")
+	storeKind := flags.String("store", jsonlScoreStoreKind, "score store kind: file, jsonl or memory")
+	dir := flags.String("data", filepath.Join(dataDir(), leaderboardDirName), "directory of the leaderboard scores")
+	if parseError := flags.Parse(args); parseError != nil {
+		return parseError
+	}
+	if *secret == "" {
+		return errors.New("leaderboard secret is not configured, use -secret")
+	}
+
+	store, storeError := NewScoreStore(*storeKind, *dir, RetentionPolicy{})
+	if storeError != nil {
+		return storeError
+	}
+
+	handler := newLeaderboardHandler(store, *secret, filepath.Join(*dir, replayDirName))
+	log.Printf("Leaderboard server listening on %s", *address)
+	io.WriteString(stdout, "Leaderboard server listening on "+*address+"\n")
+	server := &http.Server{
+		Addr:              *address,
+		Handler:           handler,
+		ReadHeaderTimeout: leaderboardReadHeaderTimeout,
+		ReadTimeout:       leaderboardReadTimeout,
+		WriteTimeout:      leaderboardWriteTimeout}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testLeaderboardSecret = "shared secret"
+
+func newTestLeaderboard(t *testing.T) (*httptest.Server, ScoreStore) {
+	store := newMemoryScoreStore()
+	server := httptest.NewServer(newLeaderboardHandler(store, testLeaderboardSecret, t.TempDir()))
+	t.Cleanup(server.Close)
+	return server, store
+}
+
+func newTestSubmission(seed int64) ScoreSubmission {
+	score := HighScore{
+		Timestamp:  time.Now().Add(-time.Minute).Truncate(time.Second),
+		Score:      42,
+		PlayerName: "tester",
+		Length:     7,
+		Ticks:      300,
+		Mode:       classicGameMode,
+		Seed:       seed}
+	replay := NewReplay(seed, 60, 20)
+	replay.Record(10, up)
+	replay.Record(25, left)
+	return ScoreSubmission{Score: score, Replay: replay}
+}
+
+// postSubmission sends the submission as is, without signing it, and returns the response status
+func postSubmission(t *testing.T, server *httptest.Server, submission *ScoreSubmission) int {
+	t.Helper()
+	payload, marshalError := json.Marshal(submission)
+	if marshalError != nil {
+		t.Fatal(marshalError)
+	}
+	response, postError := http.Post(server.URL+leaderboardScoresPath, "application/json", bytes.NewReader(payload))
+	if postError != nil {
+		t.Fatal(postError)
+	}
+	response.Body.Close()
+	return response.StatusCode
+}
+
+func TestLeaderboardAcceptsSignedSubmission(t *testing.T) {
+	server, store := newTestLeaderboard(t)
+	submission := newTestSubmission(1234)
+
+	if submitError := submitToLeaderboard(server.URL, testLeaderboardSecret, submission.Score, submission.Replay); submitError != nil {
+		t.Fatalf("signed submission rejected: %s", submitError)
+	}
+	stored, _ := store.ForPlayer("tester")
+	if len(stored) != 1 || scoreIdentity(&stored[0]) != scoreIdentity(&submission.Score) {
+		t.Errorf("stored scores: %v, expected the submitted one", stored)
+	}
+
+	// the same score submitted again is accepted, but not stored twice
+	if submitError := submitToLeaderboard(server.URL, testLeaderboardSecret, submission.Score, submission.Replay); submitError != nil {
+		t.Fatalf("repeated submission rejected: %s", submitError)
+	}
+	if stored, _ := store.ForPlayer("tester"); len(stored) != 1 {
+		t.Errorf("stored scores after the repeated submission: %d, expected: 1", len(stored))
+	}
+}
+
+func TestLeaderboardStoresSimultaneousDuplicatesOnce(t *testing.T) {
+	server, store := newTestLeaderboard(t)
+	submission := newTestSubmission(1234)
+
+	const senders = 8
+	results := make(chan error, senders)
+	for idx := 0; idx < senders; idx++ {
+		go func() {
+			results <- submitToLeaderboard(server.URL, testLeaderboardSecret, submission.Score, submission.Replay)
+		}()
+	}
+	for idx := 0; idx < senders; idx++ {
+		if submitError := <-results; submitError != nil {
+			t.Error(submitError)
+		}
+	}
+
+	if stored, _ := store.ForPlayer("tester"); len(stored) != 1 {
+		t.Errorf("stored scores: %d, expected: 1", len(stored))
+	}
+}
+
+func TestLeaderboardRejectsTamperedSignature(t *testing.T) {
+	server, store := newTestLeaderboard(t)
+	submission := newTestSubmission(1234)
+	if signError := submission.Sign(testLeaderboardSecret); signError != nil {
+		t.Fatal(signError)
+	}
+	submission.Score.Score = 9000
+
+	if status := postSubmission(t, server, &submission); status != http.StatusForbidden {
+		t.Errorf("status of the tampered submission: %d, expected: %d", status, http.StatusForbidden)
+	}
+
+	submission = newTestSubmission(1234)
+	if signError := submission.Sign("guessed secret"); signError != nil {
+		t.Fatal(signError)
+	}
+	if status := postSubmission(t, server, &submission); status != http.StatusForbidden {
+		t.Errorf("status of the submission signed with the wrong secret: %d, expected: %d", status, http.StatusForbidden)
+	}
+
+	if stored, _ := store.ForPlayer("tester"); len(stored) != 0 {
+		t.Errorf("rejected scores were stored: %v", stored)
+	}
+}
+
+func TestLeaderboardRejectsReplayOfAnotherSeed(t *testing.T) {
+	server, store := newTestLeaderboard(t)
+	submission := newTestSubmission(1234)
+	submission.Replay.Seed = 4321
+
+	submitError := submitToLeaderboard(server.URL, testLeaderboardSecret, submission.Score, submission.Replay)
+	if submitError == nil {
+		t.Error("submission with the replay of another seed is accepted")
+	}
+	if stored, _ := store.ForPlayer("tester"); len(stored) != 0 {
+		t.Errorf("rejected scores were stored: %v", stored)
+	}
+}
+
+func TestLeaderboardListsScoresAsJSON(t *testing.T) {
+	server, _ := newTestLeaderboard(t)
+	for idx, points := range []int{10, 30, 20} {
+		submission := newTestSubmission(int64(idx + 1))
+		submission.Score.Score = points
+		if submitError := submitToLeaderboard(server.URL, testLeaderboardSecret, submission.Score, submission.Replay); submitError != nil {
+			t.Fatal(submitError)
+		}
+	}
+
+	response, getError := http.Get(server.URL + leaderboardScoresPath + "?n=2")
+	if getError != nil {
+		t.Fatal(getError)
+	}
+	defer response.Body.Close()
+	if contentType := response.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("content type: %q, expected: application/json", contentType)
+	}
+
+	listed := HighScores{}
+	if decodeError := json.NewDecoder(response.Body).Decode(&listed); decodeError != nil {
+		t.Fatal(decodeError)
+	}
+	if len(listed) != 2 || listed[0].Score != 30 || listed[1].Score != 20 {
+		t.Errorf("listed scores: %v, expected the top two of 30 and 20", listed)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	t "time"
+)
+
+const leaderboardRequestTimeout = 5 * t.Second
+
+var pendingSubmissions sync.WaitGroup
+
+// submitToLeaderboard signs the score with its replay and posts it to the leaderboard server
+func submitToLeaderboard(baseURL string, secret string, score HighScore, replay *Replay) error {
+	submission := ScoreSubmission{Score: score, Replay: replay}
+	if signError := submission.Sign(secret); signError != nil {
+		return signError
+	}
+
+	payload, marshalError := json.Marshal(&submission)
+	if marshalError != nil {
+		return marshalError
+	}
+
+	client := http.Client{Timeout: leaderboardRequestTimeout}
+	url := strings.TrimRight(baseURL, "/") + leaderboardScoresPath
+	response, postError := client.Post(url, "application/json", bytes.NewReader(payload))
+	if postError != nil {
+		return postError
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusOK {
+		return fmt.Errorf("leaderboard server responded with %s", response.Status)
+	}
+	return nil
+}
+
+// submitScoreInBackground sends the score to the configured leaderboard without blocking the game
+func submitScoreInBackground(score HighScore, replay *Replay) {
+	if gameConfig.LeaderboardURL == "" {
+		return
+	}
+
+	pendingSubmissions.Add(1)
+	go func() {
+		defer pendingSubmissions.Done()
+		submitError := submitToLeaderboard(gameConfig.LeaderboardURL, gameConfig.LeaderboardSecret, score, replay)
+		if submitError != nil {
+			log.Println("Error submitting score to leaderboard:", submitError)
+			return
+		}
+		log.Printf("Score submitted to leaderboard: %s", gameConfig.LeaderboardURL)
+	}()
+}
+
+// awaitSubmissions waits for the background submissions to finish, but not longer than the timeout
+func awaitSubmissions(timeout t.Duration) {
+	done := make(chan struct{})
+	go func() {
+		pendingSubmissions.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-t.After(timeout):
+		log.Println("Leaderboard submissions did not finish in time")
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/VAlux/GSnake/widget"
+	gc "github.com/rthornton128/goncurses"
+)
+
+const (
+	loadGameWindowTitle  = "Load Game"
+	loadGameWindowWidth  = 64
+	loadGameWindowHeight = 16
+	loadGameWindowHelp   = "Enter: load  d: delete  q: close"
+	loadGameNoSaves      = "No saved games"
+	loadGameDeleteTitle  = "Delete saved game"
+)
+
+// loadGameScene shows the list of the save slots and resumes the chosen one
+type loadGameScene struct {
+	parent  *gc.Window
+	dialog  *widget.Dialog
+	games   []*SavedGame
+	list    *widget.List
+	status  *widget.Label
+	onLoad  func(saved *SavedGame) SceneTransition
+	onClose func() SceneTransition
+}
+
+// newLoadGameScene creates the load game window. onLoad is called with the chosen saved game
+// and onClose once the window is closed without loading, both return the transition to perform
+func newLoadGameScene(parent *gc.Window,
+	onLoad func(saved *SavedGame) SceneTransition,
+	onClose func() SceneTransition) *loadGameScene {
+	return &loadGameScene{
+		parent:  parent,
+		list:    widget.NewList(),
+		status:  widget.NewLabel(""),
+		onLoad:  onLoad,
+		onClose: onClose}
+}
+
+func (scene *loadGameScene) Name() string { return "loadGame" }
+
+func (scene *loadGameScene) Enter() {
+	scene.reload()
+
+	content := widget.NewColumn(scene.list, scene.status)
+	dialog, dialogError := widget.NewDialog(scene.parent, loadGameWindowHeight, loadGameWindowWidth, loadGameWindowTitle, content)
+	if dialogError != nil {
+		log.Println("Error creating load game window: ", dialogError)
+		return
+	}
+	dialog.SetFooter(loadGameWindowHelp)
+	scene.dialog = dialog
+}
+
+func (scene *loadGameScene) reload() {
+	games, listError := ListSavedGames()
+	if listError != nil {
+		log.Println("Error listing saved games: ", listError)
+		scene.status.SetText("Error reading saved games")
+	}
+	scene.games = games
+
+	descriptions := []string{}
+	for _, saved := range games {
+		descriptions = append(descriptions, describeSavedGame(saved))
+	}
+	if len(games) == 0 && listError == nil {
+		scene.status.SetText(loadGameNoSaves)
+	}
+	scene.list.SetItems(descriptions)
+}
+
+func (scene *loadGameScene) Leave() {
+	if scene.dialog != nil {
+		scene.dialog.Close()
+	}
+}
+
+func (scene *loadGameScene) HandleKey(key gc.Key) SceneTransition {
+	switch key {
+	case gc.KEY_RETURN, gc.KEY_ENTER:
+		return scene.load()
+	case 'd', gc.KEY_DC:
+		return scene.confirmDelete()
+	case 'q', escapeKey:
+		return scene.onClose()
+	default:
+		scene.list.HandleKey(key)
+	}
+	return stay()
+}
+
+func (scene *loadGameScene) load() SceneTransition {
+	if len(scene.games) == 0 {
+		return stay()
+	}
+
+	saved := scene.games[scene.list.Selected]
+	if compatibilityError := saved.Compatible(); compatibilityError != nil {
+		scene.status.SetText(compatibilityError.Error())
+		return stay()
+	}
+	log.Printf("Loading saved game %q", saved.Slot)
+	return scene.onLoad(saved)
+}
+
+// confirmDelete asks the player before deleting the selected save slot
+func (scene *loadGameScene) confirmDelete() SceneTransition {
+	if len(scene.games) == 0 {
+		return stay()
+	}
+
+	slot := scene.games[scene.list.Selected].Slot
+	question := []string{fmt.Sprintf("Delete the saved game %q?", slot)}
+	return pushScene(showConfirmation(loadGameDeleteTitle, question, scene.parent, func() SceneTransition {
+		scene.delete(slot)
+		return popScene()
+	}))
+}
+
+func (scene *loadGameScene) delete(slot string) {
+	if deleteError := DeleteSavedGame(slot); deleteError != nil {
+		log.Println("Error deleting saved game: ", deleteError)
+		scene.status.SetText("Error deleting " + slot)
+		return
+	}
+	scene.status.SetText("Deleted " + slot)
+	scene.reload()
+}
+
+func (scene *loadGameScene) Update() SceneTransition {
+	if scene.dialog == nil {
+		return scene.onClose()
+	}
+	return stay()
+}
+
+func (scene *loadGameScene) Draw() {
+	if scene.dialog != nil {
+		scene.dialog.Draw()
+	}
+}
@@ -1,49 +1,87 @@
 package main
 
 import (
+	"fmt"
+	"unicode"
+
+	"github.com/VAlux/GSnake/widget"
 	gc "github.com/rthornton128/goncurses"
 )
 
 const (
-	// MenuWindowWidth represents the width of the menu window in characters
+	// MenuWindowWidth represents the recommended width of the menu window in characters
 	MenuWindowWidth = 55
-	// MenuWindowHeight represents the height of the menu window in characters
-	MenuWindowHeight = 10
+	// MenuWindowHeight represents the recommended height of the menu window in characters
+	MenuWindowHeight = 14
 
 	menuTitle            = "Main Menu"
 	menuMark             = " => "
-	menuMarkEmpty        = "    "
-	menuItemOffset       = 5
 	menuContentTopOffset = 3
+	// menuWindowChromeWidth is the amount of columns occupied by the box and the margin after the descriptions
+	menuWindowChromeWidth = 3
+	// menuTransitionDuration is the amount of ticks the items of the opened menu slide in from the right
+	menuTransitionDuration = 4
+
+	backMenuItemTitle       = "Back"
+	backMenuItemDescription = " -- Return to the previous menu"
+
+	escapeKey gc.Key = 27
 )
 
 // Menu is an interface for interaction with Menu type
 type Menu interface {
-	HandleInput() bool
+	HandleKey(key gc.Key) bool
 	Free()
 	Refresh()
+	// Update advances the menu animations by one tick
+	Update()
 	init(stdscr *gc.Window, items []*MenuItem)
 }
 
 // MenuItemHandlerFunction represents an action point on the particular menu item
 type MenuItemHandlerFunction func() bool
 
-// MenuWindow  contains all of the ncurses main-menu realted stuff
+// MenuWindow  contains all of the ncurses main-menu realted stuff.
+// The items are chosen with the arrow keys and Enter, the hotkeys or the mouse click
 type MenuWindow struct {
-	window           *gc.Window
-	items            []*MenuItem
-	currentItemIndex int
+	window *gc.Window
+	// levels contains the items of the opened submenus, the current one last
+	levels [][]*MenuItem
+	list   *widget.List
+	// titleWidth is the width of the titles column, the same for all of the submenus
+	titleWidth int
+	// slideTicks is the amount of ticks the items slide in since the menu or the submenu is opened
+	slideTicks int
 }
 
-// MenuItem describes the title description and functionality of the menu item
+// MenuItem describes the title description and functionality of the menu item.
+// The item opens the submenu instead of calling the handler if it has any
 type MenuItem struct {
 	MenuItemTitle       string
 	MenuItemDescription string
 	MenuItemHandler     MenuItemHandlerFunction
+	// MenuItemEnabled checks if the item can be chosen once the menu is shown, the item is always enabled if it is nil
+	MenuItemEnabled func() bool
+	// MenuItemState returns the current value of the setting changed by the item, shown after its description
+	MenuItemState func() string
+	Submenu       []*MenuItem
 }
 
 func (item *MenuItem) String() string {
-	return item.MenuItemTitle + "\t" + item.MenuItemDescription
+	return item.MenuItemTitle + "\t" + item.description()
+}
+
+// description returns the description of the item followed by the value of its setting, if any
+func (item *MenuItem) description() string {
+	if item.MenuItemState == nil {
+		return item.MenuItemDescription
+	}
+	return item.MenuItemDescription + ": " + item.MenuItemState()
+}
+
+// enabled checks if the item can be chosen
+func (item *MenuItem) enabled() bool {
+	return item.MenuItemEnabled == nil || item.MenuItemEnabled()
 }
 
 // NewMenuItem creates new menu item with specified title, description and handler
@@ -55,69 +93,207 @@ func NewMenuItem(title string, description string, handler MenuItemHandlerFuncti
 	}
 }
 
-// HandleInput obtains the user input and executes actions based on it.
-func (m *MenuWindow) HandleInput() bool {
-	gc.Update()
-	ch := m.window.GetChar()
+// NewSubmenuItem creates new menu item opening the submenu of specified items
+func NewSubmenuItem(title string, description string, items []*MenuItem) *MenuItem {
+	return &MenuItem{
+		MenuItemTitle:       title,
+		MenuItemDescription: description,
+		Submenu:             items,
+	}
+}
+
+// backMenuItem is added to every submenu to return to the previous one
+var backMenuItem = NewMenuItem(backMenuItemTitle, backMenuItemDescription, nil)
 
-	switch ch {
-	case gc.KEY_DOWN:
-		m.moveCaretDown()
-	case gc.KEY_UP:
-		m.moveCaretUp()
-	case gc.KEY_RETURN:
-		return m.executeCurrentHandler()
+// HandleKey executes actions based on the user input. Returns false once the menu should be closed.
+// Escape key returns to the previous menu, or closes the menu if there is none
+func (m *MenuWindow) HandleKey(ch gc.Key) bool {
+	switch {
+	case ch == gc.KEY_RETURN || ch == gc.KEY_ENTER:
+		return m.activate(m.list.Selected)
+	case ch == escapeKey:
+		if len(m.levels) == 1 {
+			return false
+		}
+		m.back()
+	case ch == gc.KEY_MOUSE:
+		return m.handleMouse()
+	case ch < unicode.MaxASCII && m.hotkeyItem(ch) >= 0:
+		return m.activate(m.hotkeyItem(ch))
 	default:
-		break
+		m.list.HandleKey(ch)
 	}
 
 	m.Refresh()
 	return true
 }
 
-func (m *MenuWindow) moveCaretDown() {
-	if m.currentItemIndex == len(m.items)-1 {
-		m.currentItemIndex = 0
-	} else {
-		m.currentItemIndex++
+// handleMouse selects the clicked item and chooses it, the wheel moves the selection
+func (m *MenuWindow) handleMouse() bool {
+	event := gc.GetMouse()
+	if event == nil || !m.window.Enclose(event.Y, event.X) {
+		return true
 	}
+
+	switch {
+	case event.State&gc.M_B4_PRESSED != 0:
+		m.list.HandleKey(gc.KEY_UP)
+	case event.State&gc.M_B5_PRESSED != 0:
+		m.list.HandleKey(gc.KEY_DOWN)
+	case event.State&gc.M_B1_CLICKED != 0:
+		windowY, _ := m.window.YX()
+		if idx := m.list.ItemAt(event.Y - windowY); m.list.Enabled(idx) {
+			m.list.Select(idx)
+			return m.activate(idx)
+		}
+	}
+	m.Refresh()
+	return true
 }
 
-func (m *MenuWindow) moveCaretUp() {
-	if m.currentItemIndex == 0 {
-		m.currentItemIndex = len(m.items) - 1
-	} else {
-		m.currentItemIndex--
+// hotkeyItem returns the index of the enabled item of the current menu with the hotkey, -1 if there is none
+func (m *MenuWindow) hotkeyItem(ch gc.Key) int {
+	for idx, position := range m.list.Hotkeys {
+		title := []rune(m.currentItems()[idx].MenuItemTitle)
+		if position >= 0 && unicode.ToLower(title[position]) == unicode.ToLower(rune(ch)) && m.list.Enabled(idx) {
+			return idx
+		}
 	}
+	return -1
 }
 
-func (m *MenuWindow) getCurrentItem() *MenuItem {
-	return m.items[m.currentItemIndex]
+func (m *MenuWindow) currentItems() []*MenuItem {
+	return m.levels[len(m.levels)-1]
 }
 
-func (m *MenuWindow) executeCurrentHandler() bool {
-	return m.getCurrentItem().MenuItemHandler()
+// activate chooses the item: opens the submenu, returns to the previous menu or calls the handler
+func (m *MenuWindow) activate(idx int) bool {
+	if !m.list.Enabled(idx) {
+		return true
+	}
+
+	item := m.currentItems()[idx]
+	switch {
+	case item == backMenuItem:
+		m.back()
+	case len(item.Submenu) > 0:
+		m.levels = append(m.levels, append(append([]*MenuItem{}, item.Submenu...), backMenuItem))
+		m.showLevel(0)
+	default:
+		if !item.MenuItemHandler() {
+			return false
+		}
+		// the menu is kept open, the item shows the changed setting
+		m.updateItems()
+	}
+	m.Refresh()
+	return true
+}
+
+// back returns to the previous menu, selecting the item of the closed submenu
+func (m *MenuWindow) back() {
+	closed := m.currentItems()
+	m.levels = m.levels[:len(m.levels)-1]
+	for idx, item := range m.currentItems() {
+		if len(item.Submenu) > 0 && item.Submenu[0] == closed[0] {
+			m.showLevel(idx)
+			return
+		}
+	}
+	m.showLevel(0)
+}
+
+// showLevel fills the list with the items of the current menu and selects the enabled item starting from the index
+func (m *MenuWindow) showLevel(selected int) {
+	m.list.Hotkeys = menuHotkeys(m.currentItems())
+	m.updateItems()
+	m.list.SelectFirstEnabled(selected)
+	m.slideTicks = 0
+}
+
+// updateItems fills the list with the labels of the current menu items and their availability
+func (m *MenuWindow) updateItems() {
+	m.list.Items = []string{}
+	m.list.Disabled = []bool{}
+	for _, item := range m.currentItems() {
+		m.list.Items = append(m.list.Items, fmt.Sprintf("%-*s%s", m.titleWidth, item.MenuItemTitle, item.description()))
+		m.list.Disabled = append(m.list.Disabled, !item.enabled())
+	}
+}
+
+// menuHotkeys chooses the hotkey of every item: the first letter or digit of its title not used by the previous items
+func menuHotkeys(items []*MenuItem) []int {
+	used := map[rune]bool{}
+	hotkeys := []int{}
+	for _, item := range items {
+		hotkey := -1
+		for position, character := range []rune(item.MenuItemTitle) {
+			character = unicode.ToLower(character)
+			if (unicode.IsLetter(character) || unicode.IsDigit(character)) && !used[character] {
+				used[character] = true
+				hotkey = position
+				break
+			}
+		}
+		hotkeys = append(hotkeys, hotkey)
+	}
+	return hotkeys
+}
+
+// menuSize calculates the width of the titles column and the size of the window fitting the items of all submenus
+func menuSize(items []*MenuItem) (titleWidth int, height int, width int) {
+	descriptionWidth := 0
+	levels := [][]*MenuItem{items}
+	for levelIndex := 0; levelIndex < len(levels); levelIndex++ {
+		level := levels[levelIndex]
+		if levelIndex > 0 {
+			level = append(append([]*MenuItem{}, level...), backMenuItem)
+		}
+		height = maxInt(height, len(level)+menuContentTopOffset+1)
+
+		for _, item := range level {
+			titleWidth = maxInt(titleWidth, len([]rune(item.MenuItemTitle))+1)
+			descriptionWidth = maxInt(descriptionWidth, len([]rune(item.description())))
+			if len(item.Submenu) > 0 {
+				levels = append(levels, item.Submenu)
+			}
+		}
+	}
+
+	width = maxInt(len(menuTitle)+4, len(menuMark)+titleWidth+descriptionWidth+menuWindowChromeWidth)
+	return titleWidth, height, width
 }
 
 func (m *MenuWindow) init(stdscr *gc.Window, items []*MenuItem) {
 	maxY, maxX := stdscr.MaxYX()
-	gc.InitPair(1, gc.C_RED, gc.C_BLACK)
-	m.currentItemIndex = 0
-	m.items = items
-	m.window = createMenuWindow(stdscr, items, maxX, maxY)
+
+	titleWidth, height, width := menuSize(items)
+	height, width = minInt(height, maxY), minInt(width, maxX)
+	m.titleWidth = titleWidth
+	m.levels = [][]*MenuItem{items}
+	m.list = &widget.List{Marker: menuMark, Wrap: true}
+	m.showLevel(0)
+	m.window = createMenuWindow(stdscr, height, width, maxX, maxY)
+	m.list.SetBounds(widget.Rect{Y: menuContentTopOffset, X: 1, Height: height - menuContentTopOffset - 1, Width: width - 2})
 	m.window.Refresh()
 }
 
-// Refresh performs redrawing of the menu window contents
+// Update advances the sliding of the items
+func (m *MenuWindow) Update() {
+	m.slideTicks = minInt(m.slideTicks+1, menuTransitionDuration)
+}
+
+// Refresh performs redrawing of the menu window. The whole window is redrawn,
+// since it shares the screen area with the game window still updated under it
 func (m *MenuWindow) Refresh() {
-	for idx, item := range m.items {
-		if idx == m.currentItemIndex {
-			m.window.MovePrint(idx+menuContentTopOffset, 1, menuMark)
-		} else {
-			m.window.MovePrint(idx+menuContentTopOffset, 1, menuMarkEmpty)
-		}
-		m.window.MovePrint(idx+menuContentTopOffset, menuItemOffset, item.String())
-	}
+	height, width := m.window.MaxYX()
+	progress := float64(m.slideTicks) / float64(menuTransitionDuration)
+	shift := int((1 - easeOut(progress)) * float64(width-2))
+	m.list.SetBounds(widget.Rect{Y: menuContentTopOffset, X: 1 + shift, Height: height - menuContentTopOffset - 1, Width: width - 2 - shift})
+
+	m.window.Erase()
+	widget.DrawFrame(m.window, menuTitle)
+	m.list.Draw(m.window)
 	m.window.Refresh()
 }
 
@@ -127,16 +303,10 @@ func (m *MenuWindow) Free() {
 	m.window.Delete()
 }
 
-func createMenuWindow(stdscr *gc.Window, items []*MenuItem, maxX int, maxY int) *gc.Window {
-	wnd := stdscr.Derived(MenuWindowHeight, MenuWindowWidth, maxY/2-5, maxX/2-30)
+func createMenuWindow(stdscr *gc.Window, height int, width int, maxX int, maxY int) *gc.Window {
+	wnd := stdscr.Derived(height, width, (maxY-height)/2, (maxX-width)/2)
 	wnd.Keypad(true)
-	wnd.Box(0, 0)
-	wnd.ColorOn(1)
-	wnd.MovePrint(1, (MenuWindowWidth/2)-(len(menuTitle)/2), menuTitle)
-	wnd.ColorOff(1)
-	wnd.MoveAddChar(2, 0, gc.ACS_LTEE)
-	wnd.HLine(2, 1, gc.ACS_HLINE, MenuWindowWidth-2)
-	wnd.MoveAddChar(2, MenuWindowWidth-1, gc.ACS_RTEE)
+	widget.DrawFrame(wnd, menuTitle)
 	return wnd
 }
 
@@ -146,3 +316,18 @@ func NewMenu(stdscr *gc.Window, items []*MenuItem) Menu {
 	menu.init(stdscr, items)
 	return menu
 }
+
+// disabledMenuItems returns the copies of the items with the specified titles disabled
+func disabledMenuItems(items []*MenuItem, titles ...string) []*MenuItem {
+	copies := []*MenuItem{}
+	for _, item := range items {
+		itemCopy := *item
+		for _, title := range titles {
+			if item.MenuItemTitle == title {
+				itemCopy.MenuItemEnabled = func() bool { return false }
+			}
+		}
+		copies = append(copies, &itemCopy)
+	}
+	return copies
+}
@@ -3,58 +3,150 @@ package main
 import (
 	"fmt"
 	"log"
+	"strings"
 
+	"github.com/VAlux/GSnake/widget"
 	gc "github.com/rthornton128/goncurses"
 )
 
-// MessageBox representing the window with title and content
+// MessageBoxResult is the title of the button the message box is closed with
+type MessageBoxResult string
+
+// Results of the message box. The box closed with the escape key or without buttons is dismissed
+const (
+	messageBoxDismissed MessageBoxResult = ""
+	messageBoxYes       MessageBoxResult = "Yes"
+	messageBoxNo        MessageBoxResult = "No"
+)
+
+// yesNoButtons are the buttons of the confirmation
+var yesNoButtons = []MessageBoxResult{messageBoxYes, messageBoxNo}
+
+// rows and columns occupied by the title, the separator, the box itself and the margins
+const (
+	messageBoxChromeHeight = 4
+	messageBoxChromeWidth  = 4
+)
+
+// MessageBox representing the window with title and content.
+// The window is sized to fit the text, limited by Height and Width if they are set and by the screen.
+// The text is wrapped by the words and scrolled with the arrow keys if it is still too long
 type MessageBox struct {
 	Height      int
 	Width       int
 	Title       string
 	MessageText []string
+	// Buttons are shown under the text, the message box is closed by any key if there are none
+	Buttons []MessageBoxResult
+	// OnResult is called with the chosen button once the message box is closed and returns the transition to perform
+	OnResult func(result MessageBoxResult) SceneTransition
+	// Result is the button the message box is closed with
+	Result MessageBoxResult
+	dialog *widget.Dialog
+	text   *widget.TextView
+	closed bool
 }
 
-// Show creates the window and shows it as a child of specified window
-func (mBox *MessageBox) Show(s *gc.Window) {
-	log.Println(fmt.Sprintf("Creating %s window...", mBox.Title))
+// size calculates the size of the window fitting the text into the maximal size
+func (mBox *MessageBox) size(maxHeight int, maxWidth int) (int, int) {
+	if mBox.Height > 0 {
+		maxHeight = minInt(maxHeight, mBox.Height)
+	}
+	if mBox.Width > 0 {
+		maxWidth = minInt(maxWidth, mBox.Width)
+	}
 
-	lines, cols := s.MaxYX()
-	height, width := mBox.Height, mBox.Width
-	contentOffset := 3
+	contentWidth := len(mBox.Title) + 2
+	for _, line := range mBox.MessageText {
+		contentWidth = maxInt(contentWidth, len([]rune(line)))
+	}
+	buttonsWidth := 0
+	for _, button := range mBox.Buttons {
+		buttonsWidth += len(button) + 4
+	}
+	contentWidth = minInt(maxInt(contentWidth, buttonsWidth), maxWidth-messageBoxChromeWidth)
 
-	wnd, windowCreateError := createWindow(height, width, (lines/2)-height/2, (cols/2)-width/2)
-	if windowCreateError != nil {
-		log.Println(fmt.Sprintf("Error creating %s window: %s", mBox.Title, windowCreateError))
-		return
+	height := len(widget.WrapLines(mBox.MessageText, contentWidth)) + messageBoxChromeHeight
+	if len(mBox.Buttons) > 0 {
+		height += 2
 	}
+	return minInt(height, maxHeight), contentWidth + messageBoxChromeWidth
+}
+
+// Open creates the window as a child of specified window
+func (mBox *MessageBox) Open(s *gc.Window) error {
+	log.Println(fmt.Sprintf("Creating %s window...", mBox.Title))
 
-	wnd.ColorOn(1)
-	wnd.MovePrint(
-		1,
-		(width/2)-(len(mBox.Title)/2),
-		mBox.Title)
-	wnd.ColorOff(1)
+	mBox.text = widget.NewTextView(mBox.MessageText...)
+	content := widget.NewColumn(mBox.text)
+	if len(mBox.Buttons) > 0 {
+		buttons := []widget.Widget{}
+		for _, result := range mBox.Buttons {
+			result := result
+			buttons = append(buttons, widget.NewButton(string(result), func() { mBox.close(result) }))
+		}
+		content = widget.NewColumn(mBox.text, widget.NewSpacer(1), widget.NewRow(buttons...))
+	}
 
-	wnd.Box(0, 0)
-	wnd.ColorOn(3)
-	for idx, line := range mBox.MessageText {
-		wnd.MovePrint(idx+contentOffset, contentOffset, line)
+	lines, cols := s.MaxYX()
+	height, width := mBox.size(lines, cols)
+	dialog, dialogError := widget.NewDialog(s, height, width, mBox.Title, content)
+	if dialogError != nil {
+		return dialogError
 	}
-	wnd.ColorOff(3)
-	wnd.MoveAddChar(2, 0, gc.ACS_LTEE)
-	wnd.HLine(2, 1, gc.ACS_HLINE, width-2)
-	wnd.MoveAddChar(2, width-1, gc.ACS_RTEE)
-	wnd.Refresh()
 
+	mBox.dialog = dialog
 	log.Println(mBox.Title + " window created")
+	return nil
+}
+
+// HandleKey scrolls the text and chooses the button. Without the buttons any other key closes the message box.
+// The escape key dismisses the message box, the first letter of the button title presses it
+func (mBox *MessageBox) HandleKey(key gc.Key) bool {
+	if mBox.text.HandleKey(key) {
+		return true
+	}
+	if key == escapeKey || len(mBox.Buttons) == 0 {
+		mBox.close(messageBoxDismissed)
+		return false
+	}
+
+	for _, result := range mBox.Buttons {
+		if strings.ToLower(string(result[0])) == strings.ToLower(string(rune(key))) {
+			mBox.close(result)
+			return false
+		}
+	}
 
-	awaitClosingAction(wnd)
+	switch key {
+	case gc.KEY_LEFT:
+		key = gc.KEY_BTAB
+	case gc.KEY_RIGHT:
+		key = gc.KEY_TAB
+	}
+	mBox.dialog.HandleKey(key)
+	return !mBox.closed
+}
+
+func (mBox *MessageBox) close(result MessageBoxResult) {
+	mBox.Result = result
+	mBox.closed = true
+	log.Printf("%s closed: %q", mBox.Title, result)
 }
 
-func awaitClosingAction(wnd *gc.Window) {
-	for wnd.GetChar() == 0 {
-		gc.Nap(10)
+// closeTransition is performed once the message box is closed
+func (mBox *MessageBox) closeTransition() SceneTransition {
+	if mBox.OnResult != nil {
+		return mBox.OnResult(mBox.Result)
 	}
-	removeWindow(wnd)
+	return popScene()
+}
+
+// Close removes the window from the screen
+func (mBox *MessageBox) Close() {
+	mBox.dialog.Close()
+}
+
+func (mBox *MessageBox) draw() {
+	mBox.dialog.Draw()
 }
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"errors"
 	"log"
+	"strings"
+	"unicode"
 
+	"github.com/VAlux/GSnake/widget"
 	gc "github.com/rthornton128/goncurses"
 )
 
@@ -10,63 +14,183 @@ const defaultPlayerName = "Anon"
 const playerNameWindowTitle = "Player name"
 const playerNameWindowHeight = 10
 const playerNameWindowWidth = 50
+const playerNamePrompt = "Enter your name: "
+const playerNameMaxLength = 12
+const playerNameHistoryTitle = "Previous players (Tab, Enter to pick):"
+const playerNameHistoryMaxRows = 5
+const rememberPlayerNameTitle = "Remember my name"
 
-// GetPlayerName create and show the window with player name input form
-func GetPlayerName(s *gc.Window) string {
+const saveGameWindowTitle = "Save game"
+const saveSlotPrompt = "Save slot name: "
 
-	lines, cols := s.MaxYX()
-	height, width := playerNameWindowHeight, playerNameWindowWidth
+// textEntryScene shows the window with single line text input form.
+// The player name form also offers the names of the previous players and the option to remember the name
+type textEntryScene struct {
+	parent    *gc.Window
+	dialog    *widget.Dialog
+	title     string
+	input     *widget.TextInput
+	status    *widget.Label
+	validate  func(text string) error
+	onConfirm func(text string) SceneTransition
+	onCancel  func() SceneTransition
 
-	wnd, windowCreateError := createWindow(
-		height,
-		width,
-		(lines/2)-height/2,
-		(cols/2)-width/2)
+	// history lists the previously entered texts to pick from, nil if there is no list
+	history *widget.List
+	// remember is the option to skip the form next time, nil if there is no option
+	remember *widget.Checkbox
+}
+
+// newNameEntryScene creates the player name input form filled in with the last used name.
+// onConfirm is called with the entered name and returns the transition performed once the form is closed
+func newNameEntryScene(parent *gc.Window, onConfirm func(playerName string) SceneTransition) *textEntryScene {
+	scene := newTextEntryScene(parent, playerNameWindowTitle, playerNamePrompt, lastPlayerName, onConfirm, nil)
+	scene.validate = validatePlayerName
+
+	profiles, profilesLoadError := LoadProfiles()
+	if profilesLoadError != nil {
+		log.Println("Error loading player profiles: ", profilesLoadError)
+	}
+	if names := profiles.SortedNames(); len(names) > 0 {
+		scene.history = widget.NewList(names...)
+		if lastPlayerName == "" {
+			scene.input.SetText(names[0])
+		}
+	}
+	scene.remember = widget.NewCheckbox(rememberPlayerNameTitle, gameConfig.RememberPlayerName)
+	return scene
+}
+
+// newTextEntryScene creates the text input form filled in with the initial text. Empty text is replaced by the default player name.
+// onCancel is called if the form is closed with escape key, the form can not be cancelled if it is nil
+func newTextEntryScene(parent *gc.Window, title string, prompt string, initialText string,
+	onConfirm func(text string) SceneTransition, onCancel func() SceneTransition) *textEntryScene {
+	status := widget.NewLabel("")
+	status.Color = widget.TitleColor
+	return &textEntryScene{
+		parent:    parent,
+		title:     title,
+		input:     widget.NewTextInput(prompt, initialText, playerNameMaxLength),
+		status:    status,
+		validate:  func(string) error { return nil },
+		onConfirm: onConfirm,
+		onCancel:  onCancel}
+}
+
+// validatePlayerName checks that the name contains at least one letter or digit
+func validatePlayerName(name string) error {
+	for _, character := range name {
+		if unicode.IsLetter(character) || unicode.IsDigit(character) {
+			return nil
+		}
+	}
+	return errors.New("The name should contain a letter or a digit")
+}
+
+func (scene *textEntryScene) Name() string { return "textEntry" }
+
+func (scene *textEntryScene) Enter() {
+	height := playerNameWindowHeight
+	widgets := []widget.Widget{widget.NewSpacer(1), scene.input, widget.NewSpacer(1)}
+	if scene.history != nil {
+		historyRows := minInt(len(scene.history.Items), playerNameHistoryMaxRows)
+		// the list takes the place of the empty line under the input
+		height += historyRows - 1
+		widgets = append(widgets, widget.NewLabel(playerNameHistoryTitle), scene.history)
+	} else {
+		widgets = append(widgets, widget.NewSpacer(0))
+	}
+	if scene.remember != nil {
+		height++
+		widgets = append(widgets, scene.remember)
+	}
+	widgets = append(widgets, scene.status)
 
-	// we need to enable echo and cursor to be able to input something in the terminal
-	gc.Echo(true)
+	dialog, dialogError := widget.NewDialog(scene.parent, height, playerNameWindowWidth, scene.title, widget.NewColumn(widgets...))
+	if dialogError != nil {
+		log.Println("Error creating text input form window: ", dialogError)
+		return
+	}
+
+	// we need the cursor to be able to see where the text is typed
 	gc.Cursor(1)
-	defer gc.Echo(false)
-	defer gc.Cursor(0)
-	//
-
-	if windowCreateError != nil {
-		log.Println("Error creating player name input form window: ", windowCreateError)
-		return defaultPlayerName
-	}
-
-	wnd.Box(0, 0)
-	wnd.ColorOn(1)
-	wnd.MovePrint(
-		1,
-		(width/2)-(len(playerNameWindowTitle)/2),
-		playerNameWindowTitle)
-	wnd.ColorOff(1)
-
-	wnd.MoveAddChar(2, 0, gc.ACS_LTEE)
-	wnd.HLine(2, 1, gc.ACS_HLINE, width-2)
-	wnd.MoveAddChar(2, width-1, gc.ACS_RTEE)
-	wnd.Refresh()
-
-	log.Println("High score window created")
-
-	playerName := promptPlayerName(wnd)
-	log.Println("player name is: ", playerName)
-	removeWindow(wnd)
-	return playerName
+	scene.dialog = dialog
+	log.Println(scene.title + " window created")
+}
+
+func (scene *textEntryScene) Leave() {
+	if scene.dialog != nil {
+		gc.Cursor(0)
+		scene.dialog.Close()
+	}
 }
 
-func promptPlayerName(w *gc.Window) string {
-	msg := "Enter your name: "
-	row, col := w.MaxYX()
-	row, col = (row/2)-1, 4
-	w.MovePrint(row, col, msg)
+func (scene *textEntryScene) HandleKey(key gc.Key) SceneTransition {
+	switch {
+	case (key == gc.KEY_RETURN || key == gc.KEY_ENTER) && scene.history != nil && scene.dialog.Focused() == scene.history:
+		scene.input.SetText(scene.history.Items[scene.history.Selected])
+		scene.dialog.Focus(scene.input)
+	case key == gc.KEY_RETURN || key == gc.KEY_ENTER:
+		return scene.confirm()
+	case key == escapeKey && scene.onCancel != nil:
+		return scene.onCancel()
+	case scene.dialog != nil:
+		scene.dialog.HandleKey(key)
+	}
+	return stay()
+}
+
+func (scene *textEntryScene) confirm() SceneTransition {
+	text := strings.TrimSpace(scene.input.Text())
+	if text == "" {
+		text = defaultPlayerName
+	}
+	if validationError := scene.validate(text); validationError != nil {
+		scene.status.SetText(validationError.Error())
+		return stay()
+	}
+
+	if scene.remember != nil {
+		scene.rememberText(text)
+	}
+	log.Printf("%s: %s", scene.title, text)
+	return scene.onConfirm(text)
+}
+
+// forgetPlayerName clears the remembered player name, so the name form is shown after the next game again
+func forgetPlayerName() {
+	gameConfig.RememberPlayerName = false
+	gameConfig.PlayerName = ""
+	if saveError := gameConfig.Save(); saveError != nil {
+		log.Println("Error saving config: ", saveError)
+	}
+}
+
+// rememberText stores the text in the settings if the remember option is checked, or forgets it otherwise
+func (scene *textEntryScene) rememberText(text string) {
+	if !scene.remember.Checked && !gameConfig.RememberPlayerName {
+		return
+	}
+
+	gameConfig.RememberPlayerName = scene.remember.Checked
+	gameConfig.PlayerName = ""
+	if scene.remember.Checked {
+		gameConfig.PlayerName = text
+	}
+	if saveError := gameConfig.Save(); saveError != nil {
+		log.Println("Error saving config: ", saveError)
+	}
+}
+
+func (scene *textEntryScene) Update() SceneTransition {
+	if scene.dialog == nil {
+		return scene.confirm()
+	}
+	return stay()
+}
 
-	str, err := w.GetString(12)
-	if err != nil {
-		log.Panic("Error getting player name string: ", err)
-		return defaultPlayerName
+func (scene *textEntryScene) Draw() {
+	if scene.dialog != nil {
+		scene.dialog.Draw()
 	}
-	w.Refresh()
-	return str
 }
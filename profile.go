@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	t "time"
+)
+
+const profilesFilename = "profiles.json"
+
+// maxProfileHistory is the amount of the latest games kept in the profile for the trend charts
+const maxProfileHistory = 40
+
+// PlayerProfile contains the lifetime statistics of the player
+type PlayerProfile struct {
+	Name            string         `json:"name"`
+	GamesPlayed     int            `json:"gamesPlayed"`
+	TotalScore      int            `json:"totalScore"`
+	BestScore       int            `json:"bestScore"`
+	TotalFood       int            `json:"totalFood"`
+	BestLength      int            `json:"bestLength"`
+	TotalPlayTime   t.Duration     `json:"totalPlayTime"`
+	LongestSurvival t.Duration     `json:"longestSurvival"`
+	DeathsByCause   map[string]int `json:"deathsByCause"`
+	LastPlayed      t.Time         `json:"lastPlayed"`
+	// RecentScores and RecentLengths contain the results of the latest games, the oldest first
+	RecentScores  []int `json:"recentScores"`
+	RecentLengths []int `json:"recentLengths"`
+}
+
+// PlayerProfiles maps the player name to the profile
+type PlayerProfiles map[string]*PlayerProfile
+
+// AverageScore calculates the average score per game
+func (profile *PlayerProfile) AverageScore() float64 {
+	if profile.GamesPlayed == 0 {
+		return 0
+	}
+	return float64(profile.TotalScore) / float64(profile.GamesPlayed)
+}
+
+// Record adds the game result to the lifetime statistics
+func (profile *PlayerProfile) Record(result *HighScore) {
+	profile.GamesPlayed++
+	profile.TotalScore += result.Score
+	profile.TotalFood += result.TotalFoodEaten()
+	profile.TotalPlayTime += result.Duration
+	profile.BestScore = maxInt(profile.BestScore, result.Score)
+	profile.BestLength = maxInt(profile.BestLength, result.Length)
+	if result.Duration > profile.LongestSurvival {
+		profile.LongestSurvival = result.Duration
+	}
+	if result.DeathCause != "" {
+		if profile.DeathsByCause == nil {
+			profile.DeathsByCause = make(map[string]int)
+		}
+		profile.DeathsByCause[result.DeathCause]++
+	}
+	profile.LastPlayed = result.Timestamp
+	profile.RecentScores = appendLimited(profile.RecentScores, result.Score, maxProfileHistory)
+	profile.RecentLengths = appendLimited(profile.RecentLengths, result.Length, maxProfileHistory)
+}
+
+func appendLimited(values []int, value int, limit int) []int {
+	values = append(values, value)
+	if len(values) > limit {
+		values = values[len(values)-limit:]
+	}
+	return values
+}
+
+// SortedNames returns the player names, the most recently played first
+func (profiles PlayerProfiles) SortedNames() []string {
+	names := []string{}
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return profiles[names[i]].LastPlayed.After(profiles[names[j]].LastPlayed)
+	})
+	return names
+}
+
+func profilesPath() string {
+	return filepath.Join(dataDir(), profilesFilename)
+}
+
+// LoadProfiles reads all of the player profiles
+func LoadProfiles() (PlayerProfiles, error) {
+	lock, lockError := acquireFileLock(profilesPath()+".lock", false)
+	if lockError != nil {
+		return nil, lockError
+	}
+	defer lock.release()
+
+	return readProfiles(profilesPath())
+}
+
+func readProfiles(path string) (PlayerProfiles, error) {
+	profiles := make(PlayerProfiles)
+	content, readError := ioutil.ReadFile(path)
+	if os.IsNotExist(readError) {
+		return profiles, nil
+	}
+	if readError != nil {
+		return nil, readError
+	}
+
+	if parseError := json.Unmarshal(content, &profiles); parseError != nil {
+		return nil, parseError
+	}
+	return profiles, nil
+}
+
+// RecordGameInProfile adds the game result to the profile of its player
+func RecordGameInProfile(result HighScore) error {
+	return modifyProfiles(func(profiles PlayerProfiles) {
+		profile, exists := profiles[result.PlayerName]
+		if !exists {
+			profile = &PlayerProfile{Name: result.PlayerName}
+			profiles[result.PlayerName] = profile
+		}
+		profile.Record(&result)
+	})
+}
+
+// modifyProfiles performs the read-modify-write cycle of the profiles file under the exclusive lock
+func modifyProfiles(change func(PlayerProfiles)) error {
+	dir := dataDir()
+	if mkdirError := os.MkdirAll(dir, 0755); mkdirError != nil {
+		return mkdirError
+	}
+
+	lock, lockError := acquireFileLock(profilesPath()+".lock", true)
+	if lockError != nil {
+		return lockError
+	}
+	defer lock.release()
+
+	profiles, readError := readProfiles(profilesPath())
+	if readError != nil {
+		return readError
+	}
+
+	change(profiles)
+
+	content, marshalError := json.MarshalIndent(profiles, "", "  ")
+	if marshalError != nil {
+		return marshalError
+	}
+	return writeFileAtomic(profilesPath(), content, profilesPath()+".bak")
+}
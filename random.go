@@ -0,0 +1,31 @@
+package main
+
+import "math/rand"
+
+// countingSource is the random source counting the generated values,
+// so its state can be saved as the seed and the amount of draws and restored later
+type countingSource struct {
+	source rand.Source
+	seed   int64
+	draws  uint64
+}
+
+// newCountingSource creates the source with specified seed, advanced by the amount of draws
+func newCountingSource(seed int64, draws uint64) *countingSource {
+	source := &countingSource{source: rand.NewSource(seed), seed: seed}
+	for source.draws < draws {
+		source.Int63()
+	}
+	return source
+}
+
+func (source *countingSource) Int63() int64 {
+	source.draws++
+	return source.source.Int63()
+}
+
+func (source *countingSource) Seed(seed int64) {
+	source.source.Seed(seed)
+	source.seed = seed
+	source.draws = 0
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const replayDirName = "replays"
+
+// ReplayEvent is a single direction change made by the player at the specified game tick
+type ReplayEvent struct {
+	Tick      int    `json:"tick"`
+	Direction string `json:"direction"`
+}
+
+// Replay contains everything needed to reproduce the game: the food placement seed, the board and the player input
+type Replay struct {
+	Version     string        `json:"version"`
+	Seed        int64         `json:"seed"`
+	BoardWidth  int           `json:"boardWidth"`
+	BoardHeight int           `json:"boardHeight"`
+	Events      []ReplayEvent `json:"events"`
+}
+
+var currentReplay = &Replay{}
+
+var directionNames = map[*point]string{
+	up:    "up",
+	down:  "down",
+	left:  "left",
+	right: "right"}
+
+func directionName(direction *point) string {
+	if name, ok := directionNames[direction]; ok {
+		return name
+	}
+	return "nowhere"
+}
+
+// NewReplay creates the empty replay of the game with specified seed and board size
+func NewReplay(seed int64, boardWidth int, boardHeight int) *Replay {
+	return &Replay{
+		Version:     gameVersion,
+		Seed:        seed,
+		BoardWidth:  boardWidth,
+		BoardHeight: boardHeight,
+		Events:      []ReplayEvent{}}
+}
+
+// Record registers the direction change at the specified tick
+func (replay *Replay) Record(tick int, direction *point) {
+	replay.Events = append(replay.Events, ReplayEvent{Tick: tick, Direction: directionName(direction)})
+}
+
+// Validate checks that the replay belongs to the game of the score: the seeds match, the events are in order
+// within the game ticks and have the known directions. The game is not replayed, so the points, the length
+// and the eaten food of the score are not checked against the events
+func (replay *Replay) Validate(score *HighScore) error {
+	if replay.Seed != score.Seed {
+		return fmt.Errorf("replay seed %d does not match score seed %d", replay.Seed, score.Seed)
+	}
+
+	previousTick := 0
+	for idx, event := range replay.Events {
+		if event.Tick < previousTick || (score.Ticks > 0 && event.Tick > score.Ticks) {
+			return fmt.Errorf("replay event %d has invalid tick %d", idx, event.Tick)
+		}
+		if _, known := directionByName(event.Direction); !known {
+			return fmt.Errorf("replay event %d has unknown direction %q", idx, event.Direction)
+		}
+		previousTick = event.Tick
+	}
+	return nil
+}
+
+func directionByName(name string) (*point, bool) {
+	for direction, directionName := range directionNames {
+		if directionName == name {
+			return direction, true
+		}
+	}
+	return nil, false
+}
+
+var unsafeFilenameCharacters = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// replayFilename builds the file name of the replay from the score it belongs to
+func replayFilename(score *HighScore) string {
+	player := unsafeFilenameCharacters.ReplaceAllString(score.PlayerName, "_")
+	return fmt.Sprintf("%s-%s-%d.json", score.Timestamp.Format("20060102-150405"), player, score.Score)
+}
+
+// SaveReplay writes the replay of the scored game as JSON into the specified directory and returns the file path
+func SaveReplay(dir string, score *HighScore, replay *Replay) (string, error) {
+	if mkdirError := os.MkdirAll(dir, 0755); mkdirError != nil {
+		return "", mkdirError
+	}
+
+	content, marshalError := json.MarshalIndent(replay, "", "  ")
+	if marshalError != nil {
+		return "", marshalError
+	}
+
+	path := filepath.Join(dir, replayFilename(score))
+	return path, ioutil.WriteFile(path, content, 0644)
+}
@@ -0,0 +1,84 @@
+package main
+
+import "sort"
+
+// RetentionPolicy limits the amount of the stored high scores.
+// The score is kept if it is either among the best ones of its category or among the latest ones of its player.
+// Non-positive limit is not applied, so the other limit alone decides which scores are kept.
+// The policy without any positive limit keeps all of the scores.
+type RetentionPolicy struct {
+	TopPerCategory int `json:"topPerCategory"`
+	LastPerPlayer  int `json:"lastPerPlayer"`
+}
+
+func defaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		TopPerCategory: 100,
+		LastPerPlayer:  20}
+}
+
+// scoreMode returns the game mode of the score. The legacy scores have none, they were made in the classic mode
+func scoreMode(score *HighScore) string {
+	if score.Mode == "" {
+		return classicGameMode
+	}
+	return score.Mode
+}
+
+// scoreCategory returns the name of the leaderboard the score competes in: game mode and difficulty.
+// The legacy scores have neither, they were made in the classic mode on the normal difficulty
+func scoreCategory(score *HighScore) string {
+	mode, difficulty := scoreMode(score), score.Difficulty
+	if difficulty == "" {
+		difficulty = normalDifficulty
+	}
+	return mode + "/" + difficulty
+}
+
+func (policy RetentionPolicy) enabled() bool {
+	return policy.TopPerCategory > 0 || policy.LastPerPlayer > 0
+}
+
+// Apply returns the scores kept by the policy, preserving their original order
+func (policy RetentionPolicy) Apply(scores HighScores) HighScores {
+	if !policy.enabled() {
+		return scores
+	}
+
+	byCategory := make(map[string][]int)
+	byPlayer := make(map[string][]int)
+	for idx := range scores {
+		category := scoreCategory(&scores[idx])
+		byCategory[category] = append(byCategory[category], idx)
+		byPlayer[scores[idx].PlayerName] = append(byPlayer[scores[idx].PlayerName], idx)
+	}
+
+	keep := make([]bool, len(scores))
+	for _, indices := range byCategory {
+		sort.SliceStable(indices, func(i, j int) bool {
+			return scores[indices[i]].Score > scores[indices[j]].Score
+		})
+		markKept(keep, indices, policy.TopPerCategory)
+	}
+	for _, indices := range byPlayer {
+		sort.SliceStable(indices, func(i, j int) bool {
+			return scores[indices[i]].Timestamp.After(scores[indices[j]].Timestamp)
+		})
+		markKept(keep, indices, policy.LastPerPlayer)
+	}
+
+	kept := HighScores{}
+	for idx, score := range scores {
+		if keep[idx] {
+			kept = append(kept, score)
+		}
+	}
+	return kept
+}
+
+// markKept marks the first of the indices up to the limit as kept, nothing is marked if the limit is not positive
+func markKept(keep []bool, indices []int, limit int) {
+	for i := 0; i < limit && i < len(indices); i++ {
+		keep[indices[i]] = true
+	}
+}
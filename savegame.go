@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+const saveDirName = "saves"
+const saveFileExtension = ".json"
+
+// saveFormatVersion is increased once the saved game format is changed incompatibly.
+// Version 2 stores the size of the board instead of the screen
+const saveFormatVersion = 2
+
+// SavedPoint is the position on the game field
+type SavedPoint struct {
+	Y int `json:"y"`
+	X int `json:"x"`
+}
+
+// SavedGame is the complete state of the game in progress, stored in the named save slot
+type SavedGame struct {
+	FormatVersion int            `json:"formatVersion"`
+	Version       string         `json:"version"`
+	Slot          string         `json:"slot"`
+	SavedAt       time.Time      `json:"savedAt"`
+	PlayerName    string         `json:"playerName,omitempty"`
+	Mode          string         `json:"mode"`
+	ScoringState  map[string]int `json:"scoringState,omitempty"`
+	BoardWidth    int            `json:"boardWidth"`
+	BoardHeight   int            `json:"boardHeight"`
+	Snake         []SavedPoint   `json:"snake"`
+	Direction     string         `json:"direction"`
+	Food          SavedPoint     `json:"food"`
+	FoodKind      string         `json:"foodKind"`
+	Score         int            `json:"score"`
+	Seed          int64          `json:"seed"`
+	RandomDraws   uint64         `json:"randomDraws"`
+	Ticks         int            `json:"ticks"`
+	FoodEaten     map[string]int `json:"foodEaten"`
+	Replay        *Replay        `json:"replay"`
+}
+
+// Duration returns the elapsed time of the saved game
+func (saved *SavedGame) Duration() time.Duration {
+	return time.Duration(saved.Ticks) * tickDuration
+}
+
+// Compatible checks if the saved game can be resumed by this version of the game on the board of the current screen
+func (saved *SavedGame) Compatible() error {
+	if saved.FormatVersion != saveFormatVersion {
+		return fmt.Errorf("saved game format %d is not supported", saved.FormatVersion)
+	}
+	if boardWidth, boardHeight := boardSize(); saved.BoardWidth != boardWidth || saved.BoardHeight != boardHeight {
+		return fmt.Errorf("saved game is made on %dx%d board", saved.BoardWidth, saved.BoardHeight)
+	}
+	if _, known := directionByName(saved.Direction); !known || len(saved.Snake) < 2 {
+		return errors.New("saved game is damaged")
+	}
+	return nil
+}
+
+func savesDir() string {
+	return filepath.Join(dataDir(), saveDirName)
+}
+
+func savePath(slot string) string {
+	return filepath.Join(savesDir(), unsafeFilenameCharacters.ReplaceAllString(slot, "_")+saveFileExtension)
+}
+
+// currentSavedGame captures the state of the current game
+func currentSavedGame(slot string) *SavedGame {
+	body := []SavedPoint{}
+	for node := playerSnake.body.Head(); node != nil; node = node.Next {
+		position := node.Data.(point)
+		body = append(body, SavedPoint{position.y, position.x})
+	}
+
+	boardWidth, boardHeight := boardSize()
+	return &SavedGame{
+		FormatVersion: saveFormatVersion,
+		Version:       gameVersion,
+		Slot:          slot,
+		SavedAt:       time.Now(),
+		PlayerName:    lastPlayerName,
+		Mode:          scoring.Name(),
+		ScoringState:  scoring.State(),
+		BoardWidth:    boardWidth,
+		BoardHeight:   boardHeight,
+		Snake:         body,
+		Direction:     directionName(playerSnake.direction),
+		Food:          SavedPoint{currentFood.position.y, currentFood.position.x},
+		FoodKind:      currentFood.kind,
+		Score:         score,
+		Seed:          gameSeed,
+		RandomDraws:   gameRandomSource.draws,
+		Ticks:         gameTicks,
+		FoodEaten:     foodEaten,
+		Replay:        currentReplay}
+}
+
+// SaveGame writes the current game to the save slot, replacing the previously saved one
+func SaveGame(slot string) error {
+	if mkdirError := os.MkdirAll(savesDir(), 0755); mkdirError != nil {
+		return mkdirError
+	}
+
+	content, marshalError := json.MarshalIndent(currentSavedGame(slot), "", "  ")
+	if marshalError != nil {
+		return marshalError
+	}
+	return writeFileAtomic(savePath(slot), content, "")
+}
+
+// LoadSavedGame reads the game from the save file
+func LoadSavedGame(path string) (*SavedGame, error) {
+	content, readError := ioutil.ReadFile(path)
+	if readError != nil {
+		return nil, readError
+	}
+
+	saved := &SavedGame{}
+	if parseError := json.Unmarshal(content, saved); parseError != nil {
+		return nil, parseError
+	}
+	return saved, nil
+}
+
+// ListSavedGames reads all of the save slots, the most recently saved first. Unreadable files are skipped
+func ListSavedGames() ([]*SavedGame, error) {
+	paths, globError := filepath.Glob(filepath.Join(savesDir(), "*"+saveFileExtension))
+	if globError != nil {
+		return nil, globError
+	}
+
+	games := []*SavedGame{}
+	for _, path := range paths {
+		saved, loadError := LoadSavedGame(path)
+		if loadError != nil {
+			log.Println("Error reading saved game "+path+": ", loadError)
+			continue
+		}
+		games = append(games, saved)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].SavedAt.After(games[j].SavedAt)
+	})
+	return games, nil
+}
+
+// hasSavedGames checks if there is any save slot to load
+func hasSavedGames() bool {
+	paths, globError := filepath.Glob(filepath.Join(savesDir(), "*"+saveFileExtension))
+	return globError == nil && len(paths) > 0
+}
+
+// DeleteSavedGame removes the save slot
+func DeleteSavedGame(slot string) error {
+	return os.Remove(savePath(slot))
+}
+
+// restoreGame replaces the current game by the saved one and deletes its save slot
+func restoreGame(w *gc.Window, saved *SavedGame) {
+	direction, _ := directionByName(saved.Direction)
+	body := NewList()
+	for _, position := range saved.Snake {
+		body.Append(&Node{Data: point{position.Y, position.X}})
+	}
+
+	gameSeed = saved.Seed
+	gameRandomSource = newCountingSource(saved.Seed, saved.RandomDraws)
+	gameRandom = rand.New(gameRandomSource)
+	gameTicks = saved.Ticks
+	deathCause = ""
+	foodEaten = saved.FoodEaten
+	if foodEaten == nil {
+		foodEaten = make(map[string]int)
+	}
+	currentReplay = saved.Replay
+	if currentReplay == nil {
+		currentReplay = NewReplay(saved.Seed, saved.BoardWidth, saved.BoardHeight)
+	}
+	if saved.PlayerName != "" {
+		lastPlayerName = saved.PlayerName
+	}
+
+	playerSnake = &snake{body.Head(), body, direction}
+	currentFood = &food{&point{saved.Food.Y, saved.Food.X}, foodAnimationOf(regularFoodKind), regularFoodKind}
+	if saved.FoodKind == bonusFoodKind {
+		currentFood = &food{currentFood.position, foodAnimationOf(bonusFoodKind), bonusFoodKind}
+	}
+	objects = []object{playerSnake, currentFood}
+	score = saved.Score
+	scoring = NewScoringStrategy(saved.Mode)
+	scoring.Restore(saved.ScoringState)
+
+	log.Printf("Restored saved game %q with seed %d at tick %d", saved.Slot, gameSeed, gameTicks)
+	// the resumed game is consumed, so it can not be loaded again to replay the same moves for the better score
+	if deleteError := DeleteSavedGame(saved.Slot); deleteError != nil {
+		log.Println("Error deleting restored saved game: ", deleteError)
+	}
+	emitEvent(gameEvent{name: gameStartedEvent})
+	effects.Clear(w)
+	w.Erase()
+	drawWalls(w)
+	w.Refresh()
+}
+
+// defaultSaveSlot suggests the name of the save slot for the current game
+func defaultSaveSlot() string {
+	if lastPlayerName != "" {
+		return lastPlayerName
+	}
+	return "game-" + time.Now().Format("0102-1504")
+}
+
+// describeSavedGame formats the save slot line of the load game window
+func describeSavedGame(saved *SavedGame) string {
+	description := fmt.Sprintf("%-12.12s %6d %5s %-11.11s %s",
+		saved.Slot, saved.Score, formatDuration(saved.Duration()), saved.Mode, saved.SavedAt.Format("2006-01-02 15:04"))
+	if saved.Compatible() != nil {
+		description += " !"
+	}
+	return strings.TrimRight(description, " ")
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// Scene is the state of the game flow, like the title screen, the game itself or the dialog shown over it.
+// Only the topmost scene of the stack receives the input and the ticks, all of the scenes are drawn bottom to top
+type Scene interface {
+	// Name identifies the scene in the logs
+	Name() string
+	// Enter is called once the scene is pushed to the stack
+	Enter()
+	// Leave is called once the scene is removed from the stack
+	Leave()
+	// HandleKey reacts on the key pressed by the player
+	HandleKey(key gc.Key) SceneTransition
+	// Update advances the scene by one tick
+	Update() SceneTransition
+	// Draw renders the scene without updating the physical screen
+	Draw()
+}
+
+// resumableScene is notified once the scene above it is closed and it becomes the topmost one again
+type resumableScene interface {
+	Resume()
+}
+
+// backgroundScene keeps running while it is covered by the other scenes, like the dialogs.
+// It can not change the scene stack while it is in the background
+type backgroundScene interface {
+	UpdateBackground()
+}
+
+type sceneTransitionKind int
+
+const (
+	stayTransition sceneTransitionKind = iota
+	pushTransition
+	popTransition
+	replaceTransition
+	resetTransition
+	quitTransition
+)
+
+// SceneTransition is the change of the scene stack requested by its topmost scene
+type SceneTransition struct {
+	kind  sceneTransitionKind
+	scene Scene
+}
+
+// stay keeps the scene stack as it is
+func stay() SceneTransition {
+	return SceneTransition{kind: stayTransition}
+}
+
+// pushScene shows the scene over the current one
+func pushScene(scene Scene) SceneTransition {
+	return SceneTransition{kind: pushTransition, scene: scene}
+}
+
+// popScene closes the current scene, returning to the one below it
+func popScene() SceneTransition {
+	return SceneTransition{kind: popTransition}
+}
+
+// replaceScene closes the current scene and shows the specified one instead
+func replaceScene(scene Scene) SceneTransition {
+	return SceneTransition{kind: replaceTransition, scene: scene}
+}
+
+// resetScenes closes all of the scenes and shows the specified one alone, like the title screen after the game
+func resetScenes(scene Scene) SceneTransition {
+	return SceneTransition{kind: resetTransition, scene: scene}
+}
+
+// quitGame closes all of the scenes, which ends the game loop
+func quitGame() SceneTransition {
+	return SceneTransition{kind: quitTransition}
+}
+
+// SceneStack contains the active scenes, the topmost one last
+type SceneStack struct {
+	scenes []Scene
+}
+
+// Empty checks if there are no scenes left, so the game is over
+func (stack *SceneStack) Empty() bool {
+	return len(stack.scenes) == 0
+}
+
+// Top returns the topmost scene, nil if the stack is empty
+func (stack *SceneStack) Top() Scene {
+	if stack.Empty() {
+		return nil
+	}
+	return stack.scenes[len(stack.scenes)-1]
+}
+
+// Names returns the names of the scenes from the bottom to the top
+func (stack *SceneStack) Names() []string {
+	names := []string{}
+	for _, scene := range stack.scenes {
+		names = append(names, scene.Name())
+	}
+	return names
+}
+
+// Apply changes the stack according to the transition
+func (stack *SceneStack) Apply(transition SceneTransition) {
+	switch transition.kind {
+	case stayTransition:
+		return
+	case pushTransition:
+		stack.push(transition.scene)
+	case popTransition:
+		stack.pop()
+		if resumable, isResumable := stack.Top().(resumableScene); isResumable {
+			resumable.Resume()
+		}
+	case replaceTransition:
+		stack.pop()
+		stack.push(transition.scene)
+	case resetTransition:
+		for !stack.Empty() {
+			stack.pop()
+		}
+		stack.push(transition.scene)
+	case quitTransition:
+		for !stack.Empty() {
+			stack.pop()
+		}
+	}
+	log.Printf("Scenes: %s", strings.Join(stack.Names(), " > "))
+}
+
+func (stack *SceneStack) push(scene Scene) {
+	stack.scenes = append(stack.scenes, scene)
+	scene.Enter()
+}
+
+func (stack *SceneStack) pop() {
+	if top := stack.Top(); top != nil {
+		stack.scenes = stack.scenes[:len(stack.scenes)-1]
+		top.Leave()
+	}
+}
+
+// HandleKey passes the pressed key to the topmost scene. Zero key means that nothing is pressed
+func (stack *SceneStack) HandleKey(key gc.Key) {
+	if key == 0 || stack.Empty() {
+		return
+	}
+	stack.Apply(stack.Top().HandleKey(key))
+}
+
+// Update advances the covered background scenes and the topmost scene by one tick
+func (stack *SceneStack) Update() {
+	if stack.Empty() {
+		return
+	}
+	for _, scene := range stack.scenes[:len(stack.scenes)-1] {
+		if background, isBackground := scene.(backgroundScene); isBackground {
+			background.UpdateBackground()
+		}
+	}
+	stack.Apply(stack.Top().Update())
+}
+
+// Draw renders all of the scenes bottom to top and updates the physical screen once
+func (stack *SceneStack) Draw() {
+	for _, scene := range stack.scenes {
+		scene.Draw()
+	}
+	gc.Update()
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// recordingScene is the fake scene recording the calls it receives into the shared journal
+type recordingScene struct {
+	name    string
+	journal *[]string
+	// next is the transition returned by HandleKey and Update
+	next SceneTransition
+}
+
+func newRecordingScene(name string, journal *[]string) *recordingScene {
+	return &recordingScene{name: name, journal: journal, next: stay()}
+}
+
+func (scene *recordingScene) record(call string) {
+	*scene.journal = append(*scene.journal, scene.name+"."+call)
+}
+
+func (scene *recordingScene) Name() string { return scene.name }
+func (scene *recordingScene) Enter()       { scene.record("Enter") }
+func (scene *recordingScene) Leave()       { scene.record("Leave") }
+func (scene *recordingScene) Draw()        { scene.record("Draw") }
+
+func (scene *recordingScene) HandleKey(key gc.Key) SceneTransition {
+	scene.record("HandleKey")
+	return scene.next
+}
+
+func (scene *recordingScene) Update() SceneTransition {
+	scene.record("Update")
+	return scene.next
+}
+
+// resumableRecordingScene is the fake scene notified once it becomes the topmost one again
+type resumableRecordingScene struct {
+	*recordingScene
+}
+
+func (scene resumableRecordingScene) Resume() { scene.record("Resume") }
+
+// backgroundRecordingScene is the fake scene running while it is covered
+type backgroundRecordingScene struct {
+	*recordingScene
+}
+
+func (scene backgroundRecordingScene) UpdateBackground() { scene.record("UpdateBackground") }
+
+func assertJournal(t *testing.T, journal *[]string, expected ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(*journal, expected) {
+		t.Errorf("calls: %v, expected: %v", *journal, expected)
+	}
+	*journal = nil
+}
+
+func assertScenes(t *testing.T, stack *SceneStack, expected ...string) {
+	t.Helper()
+	if names := stack.Names(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("scenes: %v, expected: %v", names, expected)
+	}
+}
+
+func TestSceneStackPushAndPop(t *testing.T) {
+	journal := []string{}
+	stack := &SceneStack{}
+	bottom := resumableRecordingScene{newRecordingScene("bottom", &journal)}
+	top := newRecordingScene("top", &journal)
+
+	stack.Apply(pushScene(bottom))
+	stack.Apply(pushScene(top))
+	assertScenes(t, stack, "bottom", "top")
+	assertJournal(t, &journal, "bottom.Enter", "top.Enter")
+	if stack.Top() != top {
+		t.Errorf("top scene: %v, expected: top", stack.Top().Name())
+	}
+
+	stack.Apply(popScene())
+	assertScenes(t, stack, "bottom")
+	assertJournal(t, &journal, "top.Leave", "bottom.Resume")
+}
+
+func TestSceneStackReplace(t *testing.T) {
+	journal := []string{}
+	stack := &SceneStack{}
+	bottom := resumableRecordingScene{newRecordingScene("bottom", &journal)}
+	stack.Apply(pushScene(bottom))
+	stack.Apply(pushScene(newRecordingScene("first", &journal)))
+	journal = nil
+
+	stack.Apply(replaceScene(newRecordingScene("second", &journal)))
+	assertScenes(t, stack, "bottom", "second")
+	// the covered scene is not resumed, since it stays covered
+	assertJournal(t, &journal, "first.Leave", "second.Enter")
+}
+
+func TestSceneStackQuit(t *testing.T) {
+	journal := []string{}
+	stack := &SceneStack{}
+	stack.Apply(pushScene(newRecordingScene("bottom", &journal)))
+	stack.Apply(pushScene(newRecordingScene("top", &journal)))
+	journal = nil
+
+	stack.Apply(quitGame())
+	if !stack.Empty() {
+		t.Errorf("scenes left after quit: %v", stack.Names())
+	}
+	assertJournal(t, &journal, "top.Leave", "bottom.Leave")
+	if stack.Top() != nil {
+		t.Errorf("top scene of the empty stack: %v", stack.Top().Name())
+	}
+}
+
+func TestSceneStackReset(t *testing.T) {
+	journal := []string{}
+	stack := &SceneStack{}
+	stack.Apply(pushScene(newRecordingScene("game", &journal)))
+	stack.Apply(pushScene(newRecordingScene("gameOver", &journal)))
+	journal = nil
+
+	stack.Apply(resetScenes(newRecordingScene("title", &journal)))
+	assertScenes(t, stack, "title")
+	assertJournal(t, &journal, "gameOver.Leave", "game.Leave", "title.Enter")
+}
+
+func TestSceneStackTransitionsRequestedByTopScene(t *testing.T) {
+	journal := []string{}
+	stack := &SceneStack{}
+	bottom := resumableRecordingScene{newRecordingScene("bottom", &journal)}
+	stack.Apply(pushScene(bottom))
+	bottom.next = pushScene(newRecordingScene("dialog", &journal))
+	journal = nil
+
+	stack.HandleKey('x')
+	assertScenes(t, stack, "bottom", "dialog")
+	assertJournal(t, &journal, "bottom.HandleKey", "dialog.Enter")
+
+	// nothing pressed is not passed to the scene
+	stack.HandleKey(0)
+	assertJournal(t, &journal)
+
+	stack.Top().(*recordingScene).next = popScene()
+	stack.Update()
+	assertScenes(t, stack, "bottom")
+	assertJournal(t, &journal, "dialog.Update", "dialog.Leave", "bottom.Resume")
+}
+
+func TestSceneStackUpdatesCoveredBackgroundScenes(t *testing.T) {
+	journal := []string{}
+	stack := &SceneStack{}
+	stack.Apply(pushScene(backgroundRecordingScene{newRecordingScene("game", &journal)}))
+	stack.Apply(pushScene(newRecordingScene("menu", &journal)))
+	stack.Apply(pushScene(backgroundRecordingScene{newRecordingScene("dialog", &journal)}))
+	journal = nil
+
+	stack.Update()
+	// the topmost scene is updated normally even if it can run in the background, the plain covered scenes are frozen
+	assertJournal(t, &journal, "game.UpdateBackground", "dialog.Update")
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"log"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// playingScene runs the game itself. The game is frozen during the countdown after it is started or resumed
+type playingScene struct {
+	window    *gc.Window
+	countdown countdownOverlay
+	// saved is the game resumed instead of starting the new one, if any
+	saved *SavedGame
+}
+
+func newPlayingScene(window *gc.Window) *playingScene {
+	return &playingScene{window: window}
+}
+
+func newRestoredPlayingScene(window *gc.Window, saved *SavedGame) *playingScene {
+	return &playingScene{window: window, saved: saved}
+}
+
+func (scene *playingScene) Name() string { return "playing" }
+
+func (scene *playingScene) Enter() {
+	if scene.saved != nil {
+		restoreGame(scene.window, scene.saved)
+	} else {
+		newGame(scene.window, maxY/2, maxX/2)
+	}
+	scene.Resume()
+}
+
+func (scene *playingScene) Leave() {
+	scene.countdown.Stop(scene.window)
+}
+
+// Resume starts the countdown once the game is shown again after the pause, the dialog or the game over
+func (scene *playingScene) Resume() {
+	drawObjects(scene.window)
+	drawStats(playerSnake)
+	scene.countdown.Start(scene.window, gameConfig.Countdown)
+}
+
+func (scene *playingScene) HandleKey(key gc.Key) SceneTransition {
+	// direction changes are ignored during the countdown, so the snake can not be turned back into itself
+	if scene.countdown.Active() && key != 'p' && key != escapeKey {
+		return stay()
+	}
+
+	transition := handleInput(key, scene.window, playerSnake)
+	if transition.kind == pushTransition {
+		scene.countdown.Stop(scene.window)
+	}
+	return transition
+}
+
+func (scene *playingScene) Update() SceneTransition {
+	if scene.countdown.Active() {
+		scene.countdown.Update(scene.window)
+		return stay()
+	}
+
+	tick(scene.window)
+	drawStats(playerSnake)
+	transition := handleEvents(playerSnake, scene.window)
+	toasts.Update(scene.window)
+	scorePopups.Update(scene.window)
+	return transition
+}
+
+// UpdateBackground keeps the food animated, the effects playing and the notifications expiring while the game is paused.
+// The snake itself does not move
+func (scene *playingScene) UpdateBackground() {
+	currentFood.update(scene.window)
+	effects.Update(scene.window)
+	// once the game is over the snake is left to the death explosion
+	if deathCause == "" {
+		playerSnake.draw(scene.window)
+	}
+	currentFood.draw(scene.window)
+	toasts.Update(scene.window)
+	scorePopups.Update(scene.window)
+}
+
+func (scene *playingScene) Draw() {
+	scene.window.Touch()
+	scene.window.NoutRefresh()
+	if statsWindow != nil {
+		statsWindow.Touch()
+		statsWindow.NoutRefresh()
+	}
+	toasts.Draw()
+	scorePopups.Draw()
+	scene.countdown.Draw()
+}
+
+// pausedScene shows the main menu over the game
+type pausedScene struct {
+	window *gc.Window
+	menu   Menu
+}
+
+func newPausedScene(window *gc.Window) *pausedScene {
+	return &pausedScene{window: window}
+}
+
+func (scene *pausedScene) Name() string { return "paused" }
+
+func (scene *pausedScene) Enter() {
+	scene.menu = createMenu(scene.window)
+}
+
+func (scene *pausedScene) Leave() {
+	scene.menu.Free()
+}
+
+// HandleKey passes the key to the menu. Once the menu item is chosen, the menu is closed
+// and the scene requested by the menu event, if any, is shown instead of it
+func (scene *pausedScene) HandleKey(key gc.Key) SceneTransition {
+	if scene.menu.HandleKey(key) {
+		return stay()
+	}
+
+	transition := handleEvents(playerSnake, scene.window)
+	switch transition.kind {
+	case stayTransition:
+		return popScene()
+	case pushTransition:
+		return replaceScene(transition.scene)
+	}
+	return transition
+}
+
+func (scene *pausedScene) Update() SceneTransition {
+	scene.menu.Update()
+	return stay()
+}
+
+func (scene *pausedScene) Draw() {
+	scene.menu.Refresh()
+}
+
+// dialog is the modal window shown by the dialog scene
+type dialog interface {
+	// Open creates the window as a child of specified window
+	Open(parent *gc.Window) error
+	// HandleKey applies the key action. Returns false if the dialog should be closed
+	HandleKey(key gc.Key) bool
+	draw()
+	// Close removes the window from the screen
+	Close()
+}
+
+// closingDialog decides what happens once it is closed, instead of returning to the scene below it
+type closingDialog interface {
+	closeTransition() SceneTransition
+}
+
+// dialogScene shows the modal window until it is closed
+type dialogScene struct {
+	name   string
+	parent *gc.Window
+	dialog dialog
+	opened bool
+}
+
+func newDialogScene(name string, parent *gc.Window, content dialog) *dialogScene {
+	return &dialogScene{name: name, parent: parent, dialog: content}
+}
+
+func (scene *dialogScene) Name() string { return scene.name }
+
+func (scene *dialogScene) Enter() {
+	if openError := scene.dialog.Open(scene.parent); openError != nil {
+		log.Printf("Error creating %s window: %s", scene.name, openError)
+		return
+	}
+	scene.opened = true
+}
+
+func (scene *dialogScene) Leave() {
+	if scene.opened {
+		scene.dialog.Close()
+	}
+}
+
+func (scene *dialogScene) HandleKey(key gc.Key) SceneTransition {
+	if !scene.opened || !scene.dialog.HandleKey(key) {
+		return scene.close()
+	}
+	return stay()
+}
+
+func (scene *dialogScene) close() SceneTransition {
+	if closing, isClosing := scene.dialog.(closingDialog); isClosing {
+		return closing.closeTransition()
+	}
+	return popScene()
+}
+
+func (scene *dialogScene) Update() SceneTransition {
+	if !scene.opened {
+		return scene.close()
+	}
+	return stay()
+}
+
+func (scene *dialogScene) Draw() {
+	if scene.opened {
+		scene.dialog.draw()
+	}
+}
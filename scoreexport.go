@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	t "time"
+)
+
+const (
+	csvScoreFormat   = "csv"
+	jsonScoreFormat  = "json"
+	jsonlScoreFormat = "jsonl"
+	hscScoreFormat   = "hsc"
+	autoScoreFormat  = "auto"
+)
+
+var csvScoreHeader = []string{
+	"timestamp", "player", "score", "length", "duration", "ticks", "food",
+	"boardWidth", "boardHeight", "mode", "difficulty", "seed", "version"}
+
+// scoreFormatOf detects the format of the scores file by its extension
+func scoreFormatOf(path string, format string) (string, error) {
+	if format != autoScoreFormat && format != "" {
+		return format, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return csvScoreFormat, nil
+	case ".json":
+		return jsonScoreFormat, nil
+	case ".jsonl":
+		return jsonlScoreFormat, nil
+	case ".hsc", ".bak":
+		return hscScoreFormat, nil
+	default:
+		return "", errors.New("Unable to detect the format of " + path + ", specify it explicitly")
+	}
+}
+
+// readScoresFile reads the scores from the exported file or the score store file of any backend
+func readScoresFile(path string, format string) (HighScores, error) {
+	format, formatError := scoreFormatOf(path, format)
+	if formatError != nil {
+		return nil, formatError
+	}
+
+	switch format {
+	case hscScoreFormat:
+		return loadHighScoreFile(path)
+	case jsonlScoreFormat:
+		return readJSONLinesScores(path)
+	}
+
+	file, openError := os.Open(path)
+	if openError != nil {
+		return nil, openError
+	}
+	defer file.Close()
+
+	switch format {
+	case csvScoreFormat:
+		return decodeScoresCSV(file)
+	case jsonScoreFormat:
+		scores := HighScores{}
+		decodeError := json.NewDecoder(file).Decode(&scores)
+		return scores, decodeError
+	default:
+		return nil, errors.New("Unknown scores format: " + format)
+	}
+}
+
+// writeScores encodes the scores in the specified format
+func writeScores(writer io.Writer, scores HighScores, format string) error {
+	switch format {
+	case csvScoreFormat:
+		return encodeScoresCSV(writer, scores)
+	case jsonScoreFormat:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(scores)
+	case jsonlScoreFormat:
+		encoder := json.NewEncoder(writer)
+		for _, score := range scores {
+			if encodeError := encoder.Encode(score); encodeError != nil {
+				return encodeError
+			}
+		}
+		return nil
+	case hscScoreFormat:
+		content, serializeError := serialize(scores)
+		if serializeError != nil {
+			return serializeError
+		}
+		_, writeError := writer.Write(content)
+		return writeError
+	default:
+		return errors.New("Unknown scores format: " + format)
+	}
+}
+
+func encodeScoresCSV(writer io.Writer, scores HighScores) error {
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Write(csvScoreHeader)
+	for _, score := range scores {
+		csvWriter.Write([]string{
+			score.Timestamp.Format(t.RFC3339Nano),
+			score.PlayerName,
+			strconv.Itoa(score.Score),
+			strconv.Itoa(score.Length),
+			score.Duration.String(),
+			strconv.Itoa(score.Ticks),
+			formatFoodEaten(score.FoodEaten),
+			strconv.Itoa(score.BoardWidth),
+			strconv.Itoa(score.BoardHeight),
+			score.Mode,
+			score.Difficulty,
+			strconv.FormatInt(score.Seed, 10),
+			score.Version})
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func decodeScoresCSV(reader io.Reader) (HighScores, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	records, readError := csvReader.ReadAll()
+	if readError != nil {
+		return nil, readError
+	}
+	if len(records) == 0 {
+		return HighScores{}, nil
+	}
+
+	columns := make(map[string]int)
+	for idx, name := range records[0] {
+		columns[name] = idx
+	}
+	for _, required := range []string{"timestamp", "player", "score"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.New("CSV header lacks the required column: " + required)
+		}
+	}
+
+	scores := HighScores{}
+	for line, record := range records[1:] {
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return record[idx]
+		}
+
+		score, parseError := parseCSVScore(field)
+		if parseError != nil {
+			return nil, fmt.Errorf("line %d: %s", line+2, parseError)
+		}
+		scores = append(scores, score)
+	}
+	return scores, nil
+}
+
+func parseCSVScore(field func(string) string) (HighScore, error) {
+	score := HighScore{
+		PlayerName: field("player"),
+		Mode:       field("mode"),
+		Difficulty: field("difficulty"),
+		Version:    field("version")}
+
+	var parseError error
+	if score.Timestamp, parseError = t.Parse(t.RFC3339Nano, field("timestamp")); parseError != nil {
+		return score, parseError
+	}
+	if score.Score, parseError = strconv.Atoi(field("score")); parseError != nil {
+		return score, parseError
+	}
+	if score.FoodEaten, parseError = parseFoodEaten(field("food")); parseError != nil {
+		return score, parseError
+	}
+	if value := field("duration"); value != "" {
+		if score.Duration, parseError = t.ParseDuration(value); parseError != nil {
+			return score, parseError
+		}
+	}
+	if value := field("seed"); value != "" {
+		if score.Seed, parseError = strconv.ParseInt(value, 10, 64); parseError != nil {
+			return score, parseError
+		}
+	}
+
+	optionalInts := map[string]*int{
+		"length":      &score.Length,
+		"ticks":       &score.Ticks,
+		"boardWidth":  &score.BoardWidth,
+		"boardHeight": &score.BoardHeight}
+	for name, target := range optionalInts {
+		if value := field(name); value != "" {
+			if *target, parseError = strconv.Atoi(value); parseError != nil {
+				return score, parseError
+			}
+		}
+	}
+	return score, nil
+}
+
+// formatFoodEaten formats the food amounts as 'kind:amount' pairs separated by ';'
+func formatFoodEaten(foodEaten map[string]int) string {
+	pairs := []string{}
+	for kind, amount := range foodEaten {
+		pairs = append(pairs, kind+":"+strconv.Itoa(amount))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
+
+func parseFoodEaten(value string) (map[string]int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	foodEaten := make(map[string]int)
+	for _, pair := range strings.Split(value, ";") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.New("Malformed food amount: " + pair)
+		}
+		amount, parseError := strconv.Atoi(parts[1])
+		if parseError != nil {
+			return nil, parseError
+		}
+		foodEaten[parts[0]] = amount
+	}
+	return foodEaten, nil
+}
+
+// scoreIdentity is the key used to recognise the same score entry coming from several files
+func scoreIdentity(score *HighScore) string {
+	return fmt.Sprintf("%d|%s|%d|%d", score.Timestamp.UnixNano(), score.PlayerName, score.Score, score.Seed)
+}
+
+// mergeScores joins the score lists dropping the duplicated entries, keeping the first occurrence order
+func mergeScores(lists ...HighScores) HighScores {
+	seen := make(map[string]bool)
+	merged := HighScores{}
+	for _, scores := range lists {
+		for idx := range scores {
+			identity := scoreIdentity(&scores[idx])
+			if !seen[identity] {
+				seen[identity] = true
+				merged = append(merged, scores[idx])
+			}
+		}
+	}
+	return merged
+}
+
+// newScoresOnly returns the scores not present among the existing ones, without duplicates
+func newScoresOnly(existing HighScores, scores HighScores) HighScores {
+	seen := make(map[string]bool)
+	for idx := range existing {
+		seen[scoreIdentity(&existing[idx])] = true
+	}
+
+	result := HighScores{}
+	for idx := range scores {
+		identity := scoreIdentity(&scores[idx])
+		if !seen[identity] {
+			seen[identity] = true
+			result = append(result, scores[idx])
+		}
+	}
+	return result
+}
+
+// scoreIntegrityReport describes the problems found in the scores file
+type scoreIntegrityReport struct {
+	Valid    int
+	Damaged  int
+	Problems []string
+}
+
+// verifyScoresFile checks that every record of the file can be decoded and contains sane values
+func verifyScoresFile(path string, format string) (*scoreIntegrityReport, error) {
+	format, formatError := scoreFormatOf(path, format)
+	if formatError != nil {
+		return nil, formatError
+	}
+
+	report := &scoreIntegrityReport{}
+	var scores HighScores
+	var readError error
+	switch format {
+	case hscScoreFormat:
+		scores, readError = verifyHighScoreRecords(path, report)
+	case jsonlScoreFormat:
+		scores, readError = verifyJSONLinesRecords(path, report)
+	default:
+		scores, readError = readScoresFile(path, format)
+	}
+	if readError != nil {
+		return nil, readError
+	}
+
+	now := t.Now()
+	for idx, score := range scores {
+		problem := ""
+		switch {
+		case score.Score < 0:
+			problem = "negative score"
+		case score.Timestamp.IsZero():
+			problem = "missing timestamp"
+		case score.Timestamp.After(now):
+			problem = "timestamp in the future"
+		case score.Length < 0 || score.Duration < 0 || score.Ticks < 0:
+			problem = "negative statistics"
+		}
+
+		if problem != "" {
+			report.Damaged++
+			report.Problems = append(report.Problems, fmt.Sprintf("entry %d (%s): %s", idx+1, score.PlayerName, problem))
+		} else {
+			report.Valid++
+		}
+	}
+	return report, nil
+}
+
+// verifyHighScoreRecords decodes the encrypted score file record by record, reporting the damaged ones
+func verifyHighScoreRecords(path string, report *scoreIntegrityReport) (HighScores, error) {
+	content, readError := ioutil.ReadFile(path)
+	if readError != nil {
+		return nil, readError
+	}
+
+	if !bytes.HasPrefix(content, []byte(highScoreFileMagic)) {
+		scores, legacyError := deSerializeLegacy(content)
+		if legacyError != nil {
+			return nil, legacyError
+		}
+		return *scores, nil
+	}
+
+	scores := HighScores{}
+	content = content[len(highScoreFileMagic):]
+	for record := 1; len(content) > 0; record++ {
+		if len(content) < highScoreRecordHeaderSize {
+			report.Damaged++
+			report.Problems = append(report.Problems, fmt.Sprintf("record %d: truncated header", record))
+			break
+		}
+		recordSize := int(binary.BigEndian.Uint32(content))
+		content = content[highScoreRecordHeaderSize:]
+		if recordSize > len(content) {
+			report.Damaged++
+			report.Problems = append(report.Problems, fmt.Sprintf("record %d: truncated content", record))
+			break
+		}
+
+		score, recordError := deSerializeRecord(content[:recordSize])
+		content = content[recordSize:]
+		if recordError != nil {
+			report.Damaged++
+			report.Problems = append(report.Problems, fmt.Sprintf("record %d: %s", record, recordError))
+			continue
+		}
+		scores = append(scores, *score)
+	}
+	return scores, nil
+}
+
+// verifyJSONLinesRecords parses the scores file line by line, reporting the malformed lines
+// which are silently skipped when the scores are loaded
+func verifyJSONLinesRecords(path string, report *scoreIntegrityReport) (HighScores, error) {
+	file, openError := os.Open(path)
+	if openError != nil {
+		return nil, openError
+	}
+	defer file.Close()
+
+	scores := HighScores{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		score := HighScore{}
+		if parseError := json.Unmarshal(line, &score); parseError != nil {
+			report.Damaged++
+			report.Problems = append(report.Problems, fmt.Sprintf("line %d: %s", lineNumber, parseError))
+			continue
+		}
+		scores = append(scores, score)
+	}
+	return scores, scanner.Err()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// scoreIndexSize is the amount of the best scores kept in the index, enough for any leaderboard view
+const scoreIndexSize = 100
+
+// compactionInterval is the amount of the appended scores which triggers the compaction of the store
+const compactionInterval = 50
+
+// scoreIndex is a small sidecar file of the score store containing the leaderboard,
+// so showing the best scores does not require decoding the whole scores history
+type scoreIndex struct {
+	// DataSize is the size of the scores file the index was built for. Index is stale once it differs
+	DataSize int64
+	// Count is the amount of the scores in the scores file
+	Count int
+	// CompactedCount is the amount of the scores right after the last compaction
+	CompactedCount int
+	// Top contains the best scores in the descending order
+	Top HighScores
+}
+
+func buildScoreIndex(scores HighScores, dataSize int64) *scoreIndex {
+	return &scoreIndex{
+		DataSize:       dataSize,
+		Count:          len(scores),
+		CompactedCount: len(scores),
+		Top:            topScores(scores, scoreIndexSize, ScoreFilter{})}
+}
+
+// add registers the appended score in the index
+func (index *scoreIndex) add(score HighScore, dataSize int64) {
+	index.DataSize = dataSize
+	index.Count++
+
+	position := sort.Search(len(index.Top), func(i int) bool {
+		return index.Top[i].Score < score.Score
+	})
+	if position >= scoreIndexSize {
+		return
+	}
+
+	index.Top = append(index.Top, HighScore{})
+	copy(index.Top[position+1:], index.Top[position:])
+	index.Top[position] = score
+	if len(index.Top) > scoreIndexSize {
+		index.Top = index.Top[:scoreIndexSize]
+	}
+}
+
+// needsCompaction checks if enough scores were appended since the last compaction
+func (index *scoreIndex) needsCompaction() bool {
+	return index.Count-index.CompactedCount >= compactionInterval
+}
+
+// top returns the best n scores, if the index is able to answer the query
+func (index *scoreIndex) top(n int, filter ScoreFilter) (HighScores, bool) {
+	if filter != (ScoreFilter{}) {
+		return nil, false
+	}
+
+	complete := len(index.Top) == index.Count
+	if n <= 0 || n > len(index.Top) {
+		if !complete {
+			return nil, false
+		}
+		n = len(index.Top)
+	}
+
+	result := make(HighScores, n)
+	copy(result, index.Top[:n])
+	return result, true
+}
+
+// loadScoreIndex reads the index, returning nil if it is missing, damaged or does not match the data file
+func loadScoreIndex(path string, dataSize int64) *scoreIndex {
+	content, readError := ioutil.ReadFile(path)
+	if readError != nil {
+		return nil
+	}
+
+	decrypted, decryptionError := decrypt(content)
+	if decryptionError != nil {
+		return nil
+	}
+
+	index := new(scoreIndex)
+	if decodeError := gob.NewDecoder(bytes.NewReader(decrypted)).Decode(index); decodeError != nil {
+		return nil
+	}
+
+	if index.DataSize != dataSize {
+		return nil
+	}
+	return index
+}
+
+func saveScoreIndex(path string, index *scoreIndex) error {
+	buffer := bytes.Buffer{}
+	if encodeError := gob.NewEncoder(&buffer).Encode(index); encodeError != nil {
+		return encodeError
+	}
+
+	encrypted, encryptionError := encrypt(buffer.Bytes())
+	if encryptionError != nil {
+		return encryptionError
+	}
+
+	return writeFileAtomic(path, encrypted, "")
+}
+
+// fileSize returns the size of the file, or -1 if it does not exist
+func fileSize(path string) int64 {
+	info, statError := os.Stat(path)
+	if statError != nil {
+		return -1
+	}
+	return info.Size()
+}
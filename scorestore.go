@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"sort"
+	t "time"
+)
+
+const (
+	fileScoreStoreKind   = "file"
+	jsonlScoreStoreKind  = "jsonl"
+	memoryScoreStoreKind = "memory"
+)
+
+// ScoreStore describes the storage of the high score entries
+type ScoreStore interface {
+	// Add stores the new high score entry
+	Add(score HighScore) error
+	// Import stores the batch of the entries at once, applying the retention policy after all of them are added.
+	// Returns the amount of the imported entries kept by the policy
+	Import(scores HighScores) (int, error)
+	// Top returns at most n best scores matching the filter, in descending score order. n <= 0 means no limit
+	Top(n int, filter ScoreFilter) (HighScores, error)
+	// ForPlayer returns all of the scores of the specified player in chronological order
+	ForPlayer(playerName string) (HighScores, error)
+	// Delete removes all of the scores matching the filter and returns the amount of removed entries
+	Delete(filter ScoreFilter) (int, error)
+	// Stats returns the aggregated statistics of the stored scores
+	Stats() (ScoreStats, error)
+	// Compact removes the scores not retained by the policy and returns the amount of removed entries
+	Compact(policy RetentionPolicy) (int, error)
+}
+
+// ScoreFilter selects the high score entries. Zero value fields are not taken into account.
+// The legacy scores without the mode match the classic one
+type ScoreFilter struct {
+	PlayerName string
+	Mode       string
+	Since      t.Time
+	Until      t.Time
+}
+
+// ScoreStats is an aggregated summary of the high score entries
+type ScoreStats struct {
+	Count        int
+	PlayerCount  int
+	TotalScore   int
+	AverageScore float64
+	Best         HighScore
+}
+
+var scoreStore ScoreStore = newMemoryScoreStore()
+
+// Match checks if the high score entry satisfies all of the filter conditions
+func (filter ScoreFilter) Match(score *HighScore) bool {
+	if filter.PlayerName != "" && filter.PlayerName != score.PlayerName {
+		return false
+	}
+	if filter.Mode != "" && filter.Mode != scoreMode(score) {
+		return false
+	}
+	if !filter.Since.IsZero() && score.Timestamp.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && score.Timestamp.After(filter.Until) {
+		return false
+	}
+	return true
+}
+
+// NewScoreStore creates the high score store of the specified kind, keeping its files in the specified directory.
+// Persistent stores are compacted automatically using the retention policy
+func NewScoreStore(kind string, dir string, retention RetentionPolicy) (ScoreStore, error) {
+	if kind != memoryScoreStoreKind {
+		if mkdirError := os.MkdirAll(dir, 0755); mkdirError != nil {
+			return nil, mkdirError
+		}
+	}
+
+	switch kind {
+	case fileScoreStoreKind, "":
+		return newFileScoreStore(dir, retention), nil
+	case jsonlScoreStoreKind:
+		return newJSONLinesScoreStore(dir, retention), nil
+	case memoryScoreStoreKind:
+		return newMemoryScoreStore(), nil
+	default:
+		return nil, errors.New("Unknown score store kind: " + kind)
+	}
+}
+
+func topScores(scores HighScores, n int, filter ScoreFilter) HighScores {
+	top := HighScores{}
+	for idx := range scores {
+		if filter.Match(&scores[idx]) {
+			top = append(top, scores[idx])
+		}
+	}
+
+	sort.Stable(top)
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+func playerScores(scores HighScores, playerName string) HighScores {
+	result := HighScores{}
+	for _, score := range scores {
+		if score.PlayerName == playerName {
+			result = append(result, score)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
+
+// removeScores splits the scores into the ones that are kept and the amount of removed ones
+func removeScores(scores HighScores, filter ScoreFilter) (HighScores, int) {
+	kept := HighScores{}
+	for idx := range scores {
+		if !filter.Match(&scores[idx]) {
+			kept = append(kept, scores[idx])
+		}
+	}
+	return kept, len(scores) - len(kept)
+}
+
+func scoreStats(scores HighScores) ScoreStats {
+	stats := ScoreStats{Count: len(scores)}
+	players := make(map[string]bool)
+
+	for _, score := range scores {
+		players[score.PlayerName] = true
+		stats.TotalScore += score.Score
+		if score.Score > stats.Best.Score {
+			stats.Best = score
+		}
+	}
+
+	stats.PlayerCount = len(players)
+	if stats.Count > 0 {
+		stats.AverageScore = float64(stats.TotalScore) / float64(stats.Count)
+	}
+	return stats
+}
+
+// retainImported applies the retention policy to the existing scores joined with the imported ones.
+// Returns the kept scores and the amount of the imported scores among them
+func retainImported(existing HighScores, imported HighScores, policy RetentionPolicy) (HighScores, int) {
+	kept := policy.Apply(append(append(HighScores{}, existing...), imported...))
+
+	importedIdentities := make(map[string]bool)
+	for idx := range imported {
+		importedIdentities[scoreIdentity(&imported[idx])] = true
+	}
+	keptImported := 0
+	for idx := range kept {
+		if importedIdentities[scoreIdentity(&kept[idx])] {
+			keptImported++
+		}
+	}
+	return kept, keptImported
+}
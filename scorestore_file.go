@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const highScoreFilename = "score.hsc"
+
+// fileScoreStore keeps the high scores in the encrypted append-only file with a rotating backup
+// and the leaderboard index. New scores are appended as single records, the file is rewritten
+// only during the compaction or deletion, the previous version of the file is kept as the backup then.
+type fileScoreStore struct {
+	mutex      sync.Mutex
+	path       string
+	backupPath string
+	lockPath   string
+	indexPath  string
+	legacyPath string
+	retention  RetentionPolicy
+}
+
+func newFileScoreStore(dir string, retention RetentionPolicy) *fileScoreStore {
+	path := filepath.Join(dir, highScoreFilename)
+	return &fileScoreStore{
+		path:       path,
+		backupPath: path + ".bak",
+		lockPath:   path + ".lock",
+		indexPath:  path + ".idx",
+		legacyPath: highScoreFilename,
+		retention:  retention}
+}
+
+// Add appends the high score record to file.
+// Everything is done under the exclusive file lock,
+// so concurrently finishing game instances do not overwrite each other's scores.
+func (store *fileScoreStore) Add(score HighScore) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	lock, lockError := acquireFileLock(store.lockPath, true)
+	if lockError != nil {
+		log.Println("Error locking high score file:", lockError)
+		return lockError
+	}
+	defer lock.release()
+
+	if !store.isAppendable() {
+		// missing or legacy format file is converted by rewriting it completely,
+		// the partial record left by the crash is dropped, so the new one is not appended after it
+		scores, primaryValid := store.loadLocked()
+		return store.rewrite(append(scores, score), primaryValid)
+	}
+
+	sizeBefore := fileSize(store.path)
+	if appendError := store.appendRecord(&score); appendError != nil {
+		return appendError
+	}
+	log.Printf("High score successfully saved to file: %s", store.path)
+
+	index := loadScoreIndex(store.indexPath, sizeBefore)
+	if index == nil {
+		scores, _ := store.loadLocked()
+		index = buildScoreIndex(scores, fileSize(store.path))
+	} else {
+		index.add(score, fileSize(store.path))
+	}
+
+	if index.needsCompaction() {
+		_, compactionError := store.compactLocked(store.retention)
+		return compactionError
+	}
+	return saveScoreIndex(store.indexPath, index)
+}
+
+// Import rewrites the file once with the scores added and the retention policy applied
+func (store *fileScoreStore) Import(scores HighScores) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	lock, lockError := acquireFileLock(store.lockPath, true)
+	if lockError != nil {
+		return 0, lockError
+	}
+	defer lock.release()
+
+	existing, primaryValid := store.loadLocked()
+	kept, keptImported := retainImported(existing, scores, store.retention)
+	return keptImported, store.rewrite(kept, primaryValid)
+}
+
+// Top returns the best scores matching the filter, using the index when possible
+func (store *fileScoreStore) Top(n int, filter ScoreFilter) (HighScores, error) {
+	lock, lockError := acquireFileLock(store.lockPath, false)
+	if lockError != nil {
+		return nil, lockError
+	}
+	defer lock.release()
+
+	if index := loadScoreIndex(store.indexPath, fileSize(store.path)); index != nil {
+		if top, ok := index.top(n, filter); ok {
+			return top, nil
+		}
+	}
+
+	scores, loadError := store.loadWithFallback()
+	if loadError != nil {
+		return nil, loadError
+	}
+	return topScores(scores, n, filter), nil
+}
+
+// ForPlayer returns all of the scores of the player
+func (store *fileScoreStore) ForPlayer(playerName string) (HighScores, error) {
+	scores, loadError := store.load()
+	if loadError != nil {
+		return nil, loadError
+	}
+	return playerScores(scores, playerName), nil
+}
+
+// Delete removes the scores matching the filter
+func (store *fileScoreStore) Delete(filter ScoreFilter) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	lock, lockError := acquireFileLock(store.lockPath, true)
+	if lockError != nil {
+		return 0, lockError
+	}
+	defer lock.release()
+
+	scores, primaryValid := store.loadLocked()
+	kept, removed := removeScores(scores, filter)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, store.rewrite(kept, primaryValid)
+}
+
+// Compact drops the scores not retained by the policy
+func (store *fileScoreStore) Compact(policy RetentionPolicy) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	lock, lockError := acquireFileLock(store.lockPath, true)
+	if lockError != nil {
+		return 0, lockError
+	}
+	defer lock.release()
+
+	return store.compactLocked(policy)
+}
+
+// Stats returns the summary of all of the stored scores
+func (store *fileScoreStore) Stats() (ScoreStats, error) {
+	scores, loadError := store.load()
+	if loadError != nil {
+		return ScoreStats{}, loadError
+	}
+	return scoreStats(scores), nil
+}
+
+func (store *fileScoreStore) compactLocked(policy RetentionPolicy) (int, error) {
+	scores, primaryValid := store.loadLocked()
+	kept := policy.Apply(scores)
+	log.Printf("Compacting high score file: %d of %d scores retained", len(kept), len(scores))
+	return len(scores) - len(kept), store.rewrite(kept, primaryValid)
+}
+
+// isAppendable checks if the current file exists, has the append-only format and ends with the complete record
+func (store *fileScoreStore) isAppendable() bool {
+	content, readError := ioutil.ReadFile(store.path)
+	if readError != nil || !bytes.HasPrefix(content, []byte(highScoreFileMagic)) {
+		return false
+	}
+	if completeRecordsSize(content) != len(content) {
+		log.Printf("High score file %s ends with the incomplete record", store.path)
+		return false
+	}
+	return true
+}
+
+func (store *fileScoreStore) appendRecord(score *HighScore) error {
+	record, serializeError := serializeRecord(score)
+	if serializeError != nil {
+		return serializeError
+	}
+
+	file, openError := os.OpenFile(store.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if openError != nil {
+		return openError
+	}
+	defer file.Close()
+
+	if _, writeError := file.Write(record); writeError != nil {
+		log.Println("Error saving high score to file:", writeError)
+		return writeError
+	}
+	return file.Sync()
+}
+
+// rewrite atomically replaces the whole file content and rebuilds the index.
+// The backup is rotated only when the current file is known to be good,
+// otherwise the corrupted file would replace the last valid backup
+func (store *fileScoreStore) rewrite(scores HighScores, rotateBackup bool) error {
+	payload, serializeError := serialize(scores)
+	if serializeError != nil {
+		log.Println("Error serializing high scores:", serializeError)
+		return serializeError
+	}
+
+	backupPath := ""
+	if rotateBackup {
+		backupPath = store.backupPath
+	}
+
+	saveError := writeFileAtomic(store.path, payload, backupPath)
+	if saveError != nil {
+		log.Println("Error saving high score to file:", saveError)
+		return saveError
+	}
+
+	log.Printf("High score file successfully written: %s", store.path)
+	return saveScoreIndex(store.indexPath, buildScoreIndex(scores, fileSize(store.path)))
+}
+
+// loadLocked reads the scores while the exclusive lock is already held.
+// Returns whether the scores were read from the primary file
+func (store *fileScoreStore) loadLocked() (HighScores, bool) {
+	scores, primaryLoadError := loadHighScoreFile(store.path)
+	if primaryLoadError == nil {
+		return scores, true
+	}
+
+	fallbackScores, _ := store.loadFallback()
+	return fallbackScores, false
+}
+
+// load reads high score structure from file.
+// If the file is missing or damaged, the scores are read from the backup file.
+func (store *fileScoreStore) load() (HighScores, error) {
+	lock, lockError := acquireFileLock(store.lockPath, false)
+	if lockError != nil {
+		return nil, lockError
+	}
+	defer lock.release()
+
+	return store.loadWithFallback()
+}
+
+func (store *fileScoreStore) loadWithFallback() (HighScores, error) {
+	scores, loadError := loadHighScoreFile(store.path)
+	if loadError == nil {
+		return scores, nil
+	}
+
+	fallbackScores, fallbackError := store.loadFallback()
+	if fallbackError != nil {
+		if os.IsNotExist(loadError) && os.IsNotExist(fallbackError) {
+			// the fresh install has no scores yet
+			return HighScores{}, nil
+		}
+		return nil, loadError
+	}
+
+	log.Printf("High score file is unreadable (%s), using the fallback scores", loadError)
+	return fallbackScores, nil
+}
+
+// loadFallback reads the backup file, or the file in the working directory left by the older game versions
+func (store *fileScoreStore) loadFallback() (HighScores, error) {
+	scores, backupError := loadHighScoreFile(store.backupPath)
+	if backupError == nil {
+		return scores, nil
+	}
+
+	if _, statError := os.Stat(store.path); os.IsNotExist(statError) {
+		legacyScores, legacyError := loadHighScoreFile(store.legacyPath)
+		if legacyError == nil {
+			return legacyScores, nil
+		}
+	}
+	return nil, backupError
+}
+
+func loadHighScoreFile(filename string) (HighScores, error) {
+	log.Printf("Loading high score from file: %s", filename)
+	file, readError := os.Open(filename)
+	if readError != nil {
+		return nil, readError
+	}
+	defer file.Close()
+
+	scores, deSerializeError := deSerialize(file)
+	if deSerializeError != nil {
+		return nil, deSerializeError
+	}
+
+	return *scores, nil
+}
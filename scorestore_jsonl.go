@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const jsonLinesScoreFilename = "scores.jsonl"
+
+// jsonLinesScoreStore keeps the high scores in the append-only plain text file, one JSON object per line.
+// Adding a score never rewrites the existing entries, except for the periodic compaction.
+type jsonLinesScoreStore struct {
+	mutex     sync.Mutex
+	path      string
+	lockPath  string
+	indexPath string
+	retention RetentionPolicy
+}
+
+func newJSONLinesScoreStore(dir string, retention RetentionPolicy) *jsonLinesScoreStore {
+	path := filepath.Join(dir, jsonLinesScoreFilename)
+	return &jsonLinesScoreStore{
+		path:      path,
+		lockPath:  path + ".lock",
+		indexPath: path + ".idx",
+		retention: retention}
+}
+
+// Add appends the score as the new line of the file
+func (store *jsonLinesScoreStore) Add(score HighScore) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	line, marshalError := json.Marshal(score)
+	if marshalError != nil {
+		return marshalError
+	}
+
+	lock, lockError := acquireFileLock(store.lockPath, true)
+	if lockError != nil {
+		return lockError
+	}
+	defer lock.release()
+
+	sizeBefore := fileSize(store.path)
+	file, openError := os.OpenFile(store.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if openError != nil {
+		return openError
+	}
+
+	// the last line cut off by the crash is ended, so the new score is not joined to it
+	if !endsWithNewline(file, sizeBefore) {
+		line = append([]byte{'\n'}, line...)
+	}
+	_, writeError := file.Write(append(line, '\n'))
+	if writeError == nil {
+		writeError = file.Sync()
+	}
+	file.Close()
+	if writeError != nil {
+		return writeError
+	}
+	log.Printf("High score successfully appended to file: %s", store.path)
+
+	index := loadScoreIndex(store.indexPath, sizeBefore)
+	if index == nil {
+		scores, _ := store.readScores()
+		index = buildScoreIndex(scores, fileSize(store.path))
+	} else {
+		index.add(score, fileSize(store.path))
+	}
+
+	if index.needsCompaction() {
+		_, compactionError := store.compactLocked(store.retention)
+		return compactionError
+	}
+	return saveScoreIndex(store.indexPath, index)
+}
+
+// endsWithNewline checks if the file of the specified size is empty or its last line is complete
+func endsWithNewline(file *os.File, size int64) bool {
+	if size <= 0 {
+		return true
+	}
+	last := make([]byte, 1)
+	if _, readError := file.ReadAt(last, size-1); readError != nil {
+		return false
+	}
+	return last[0] == '\n'
+}
+
+// Import rewrites the file once with the scores added and the retention policy applied
+func (store *jsonLinesScoreStore) Import(scores HighScores) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	lock, lockError := acquireFileLock(store.lockPath, true)
+	if lockError != nil {
+		return 0, lockError
+	}
+	defer lock.release()
+
+	existing, loadError := store.readScores()
+	if loadError != nil {
+		return 0, loadError
+	}
+
+	kept, keptImported := retainImported(existing, scores, store.retention)
+	return keptImported, store.rewrite(kept)
+}
+
+// Top returns the best scores matching the filter, using the index when possible
+func (store *jsonLinesScoreStore) Top(n int, filter ScoreFilter) (HighScores, error) {
+	lock, lockError := acquireFileLock(store.lockPath, false)
+	if lockError != nil {
+		return nil, lockError
+	}
+	defer lock.release()
+
+	if index := loadScoreIndex(store.indexPath, fileSize(store.path)); index != nil {
+		if top, ok := index.top(n, filter); ok {
+			return top, nil
+		}
+	}
+
+	scores, loadError := store.readScores()
+	if loadError != nil {
+		return nil, loadError
+	}
+	return topScores(scores, n, filter), nil
+}
+
+// ForPlayer returns all of the scores of the player
+func (store *jsonLinesScoreStore) ForPlayer(playerName string) (HighScores, error) {
+	scores, loadError := store.load()
+	if loadError != nil {
+		return nil, loadError
+	}
+	return playerScores(scores, playerName), nil
+}
+
+// Delete rewrites the file without the scores matching the filter
+func (store *jsonLinesScoreStore) Delete(filter ScoreFilter) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	lock, lockError := acquireFileLock(store.lockPath, true)
+	if lockError != nil {
+		return 0, lockError
+	}
+	defer lock.release()
+
+	scores, loadError := store.readScores()
+	if loadError != nil {
+		return 0, loadError
+	}
+
+	kept, removed := removeScores(scores, filter)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, store.rewrite(kept)
+}
+
+// Compact drops the scores not retained by the policy
+func (store *jsonLinesScoreStore) Compact(policy RetentionPolicy) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	lock, lockError := acquireFileLock(store.lockPath, true)
+	if lockError != nil {
+		return 0, lockError
+	}
+	defer lock.release()
+
+	return store.compactLocked(policy)
+}
+
+func (store *jsonLinesScoreStore) compactLocked(policy RetentionPolicy) (int, error) {
+	scores, loadError := store.readScores()
+	if loadError != nil {
+		return 0, loadError
+	}
+
+	kept := policy.Apply(scores)
+	log.Printf("Compacting high score file: %d of %d scores retained", len(kept), len(scores))
+	return len(scores) - len(kept), store.rewrite(kept)
+}
+
+// rewrite atomically replaces the whole file content and rebuilds the index
+func (store *jsonLinesScoreStore) rewrite(scores HighScores) error {
+	content := bytes.Buffer{}
+	encoder := json.NewEncoder(&content)
+	for _, score := range scores {
+		if encodeError := encoder.Encode(score); encodeError != nil {
+			return encodeError
+		}
+	}
+
+	if writeError := writeFileAtomic(store.path, content.Bytes(), ""); writeError != nil {
+		return writeError
+	}
+	return saveScoreIndex(store.indexPath, buildScoreIndex(scores, fileSize(store.path)))
+}
+
+// Stats returns the summary of all of the stored scores
+func (store *jsonLinesScoreStore) Stats() (ScoreStats, error) {
+	scores, loadError := store.load()
+	if loadError != nil {
+		return ScoreStats{}, loadError
+	}
+	return scoreStats(scores), nil
+}
+
+func (store *jsonLinesScoreStore) load() (HighScores, error) {
+	lock, lockError := acquireFileLock(store.lockPath, false)
+	if lockError != nil {
+		return nil, lockError
+	}
+	defer lock.release()
+
+	return store.readScores()
+}
+
+// readScores reads the scores file, the missing file of the fresh install has no scores yet
+func (store *jsonLinesScoreStore) readScores() (HighScores, error) {
+	scores, readError := readJSONLinesScores(store.path)
+	if os.IsNotExist(readError) {
+		return HighScores{}, nil
+	}
+	return scores, readError
+}
+
+// readJSONLinesScores parses the scores file skipping the malformed lines,
+// e.g. the incomplete last line left after the crash during the append
+func readJSONLinesScores(path string) (HighScores, error) {
+	log.Printf("Loading high score from file: %s", path)
+	file, openError := os.Open(path)
+	if openError != nil {
+		return nil, openError
+	}
+	defer file.Close()
+
+	scores := HighScores{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		score := HighScore{}
+		if parseError := json.Unmarshal(line, &score); parseError != nil {
+			log.Printf("Skipping malformed high score at %s:%d: %s", path, lineNumber, parseError)
+			continue
+		}
+		scores = append(scores, score)
+	}
+
+	return scores, scanner.Err()
+}
@@ -0,0 +1,69 @@
+package main
+
+import "sync"
+
+// memoryScoreStore keeps the high scores in memory only. Used in tests and when the storage is not available
+type memoryScoreStore struct {
+	mutex  sync.Mutex
+	scores HighScores
+}
+
+func newMemoryScoreStore() *memoryScoreStore {
+	return &memoryScoreStore{scores: HighScores{}}
+}
+
+// Add stores the score
+func (store *memoryScoreStore) Add(score HighScore) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.scores = append(store.scores, score)
+	return nil
+}
+
+// Import stores the scores, the retention is not applied to the memory store, like to the added scores
+func (store *memoryScoreStore) Import(scores HighScores) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.scores = append(store.scores, scores...)
+	return len(scores), nil
+}
+
+// Top returns the best scores matching the filter
+func (store *memoryScoreStore) Top(n int, filter ScoreFilter) (HighScores, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return topScores(store.scores, n, filter), nil
+}
+
+// ForPlayer returns all of the scores of the player
+func (store *memoryScoreStore) ForPlayer(playerName string) (HighScores, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return playerScores(store.scores, playerName), nil
+}
+
+// Delete removes the scores matching the filter
+func (store *memoryScoreStore) Delete(filter ScoreFilter) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	kept, removed := removeScores(store.scores, filter)
+	store.scores = kept
+	return removed, nil
+}
+
+// Stats returns the summary of all of the stored scores
+func (store *memoryScoreStore) Stats() (ScoreStats, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return scoreStats(store.scores), nil
+}
+
+// Compact drops the scores not retained by the policy
+func (store *memoryScoreStore) Compact(policy RetentionPolicy) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	kept := policy.Apply(store.scores)
+	removed := len(store.scores) - len(kept)
+	store.scores = kept
+	return removed, nil
+}
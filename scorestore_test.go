@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testScoreStoreKinds = []string{memoryScoreStoreKind, jsonlScoreStoreKind, fileScoreStoreKind}
+
+var testScoreTime = time.Date(2020, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+func testScore(player string, points int, mode string, minutes int) HighScore {
+	return HighScore{
+		Timestamp:  testScoreTime.Add(time.Duration(minutes) * time.Minute),
+		Score:      points,
+		PlayerName: player,
+		Mode:       mode,
+		Difficulty: normalDifficulty}
+}
+
+// newTestScoreStore creates the store of the kind in the temporary directory, without the retention
+func newTestScoreStore(t *testing.T, kind string) ScoreStore {
+	t.Helper()
+	store, storeError := NewScoreStore(kind, t.TempDir(), RetentionPolicy{})
+	if storeError != nil {
+		t.Fatal(storeError)
+	}
+	return store
+}
+
+// forEachScoreStore runs the contract test against every store kind filled with the scores
+func forEachScoreStore(t *testing.T, scores HighScores, test func(t *testing.T, store ScoreStore)) {
+	for _, kind := range testScoreStoreKinds {
+		t.Run(kind, func(t *testing.T) {
+			store := newTestScoreStore(t, kind)
+			for _, score := range scores {
+				if addError := store.Add(score); addError != nil {
+					t.Fatal(addError)
+				}
+			}
+			test(t, store)
+		})
+	}
+}
+
+func assertScores(t *testing.T, scores HighScores, expected ...HighScore) {
+	t.Helper()
+	if len(scores) != len(expected) {
+		t.Fatalf("scores: %v, expected: %v", scores, expected)
+	}
+	for idx := range scores {
+		if scoreIdentity(&scores[idx]) != scoreIdentity(&expected[idx]) || scores[idx].Mode != expected[idx].Mode {
+			t.Errorf("score %d: %v, expected: %v", idx, scores[idx], expected[idx])
+		}
+	}
+}
+
+var (
+	aliceClassic = testScore("alice", 30, classicGameMode, 1)
+	bobClassic   = testScore("bob", 50, classicGameMode, 2)
+	aliceMaze    = testScore("alice", 70, "maze", 3)
+	aliceLatest  = testScore("alice", 10, classicGameMode, 4)
+	testScores   = HighScores{aliceClassic, bobClassic, aliceMaze, aliceLatest}
+)
+
+func TestScoreStoreFreshIsEmpty(t *testing.T) {
+	forEachScoreStore(t, nil, func(t *testing.T, store ScoreStore) {
+		top, topError := store.Top(10, ScoreFilter{})
+		if topError != nil {
+			t.Fatal(topError)
+		}
+		assertScores(t, top)
+
+		stats, statsError := store.Stats()
+		if statsError != nil {
+			t.Fatal(statsError)
+		}
+		if stats.Count != 0 {
+			t.Errorf("scores counted in the fresh store: %d", stats.Count)
+		}
+	})
+}
+
+func TestScoreStoreTop(t *testing.T) {
+	forEachScoreStore(t, testScores, func(t *testing.T, store ScoreStore) {
+		top, topError := store.Top(0, ScoreFilter{})
+		if topError != nil {
+			t.Fatal(topError)
+		}
+		assertScores(t, top, aliceMaze, bobClassic, aliceClassic, aliceLatest)
+
+		top, topError = store.Top(2, ScoreFilter{Mode: classicGameMode})
+		if topError != nil {
+			t.Fatal(topError)
+		}
+		assertScores(t, top, bobClassic, aliceClassic)
+
+		top, topError = store.Top(0, ScoreFilter{PlayerName: "alice", Since: aliceClassic.Timestamp.Add(time.Second)})
+		if topError != nil {
+			t.Fatal(topError)
+		}
+		assertScores(t, top, aliceMaze, aliceLatest)
+	})
+}
+
+func TestScoreStoreForPlayer(t *testing.T) {
+	forEachScoreStore(t, testScores, func(t *testing.T, store ScoreStore) {
+		scores, loadError := store.ForPlayer("alice")
+		if loadError != nil {
+			t.Fatal(loadError)
+		}
+		assertScores(t, scores, aliceClassic, aliceMaze, aliceLatest)
+
+		scores, loadError = store.ForPlayer("nobody")
+		if loadError != nil {
+			t.Fatal(loadError)
+		}
+		assertScores(t, scores)
+	})
+}
+
+func TestScoreStoreDelete(t *testing.T) {
+	forEachScoreStore(t, testScores, func(t *testing.T, store ScoreStore) {
+		removed, deleteError := store.Delete(ScoreFilter{PlayerName: "alice", Mode: classicGameMode})
+		if deleteError != nil {
+			t.Fatal(deleteError)
+		}
+		if removed != 2 {
+			t.Errorf("removed scores: %d, expected: 2", removed)
+		}
+
+		top, topError := store.Top(0, ScoreFilter{})
+		if topError != nil {
+			t.Fatal(topError)
+		}
+		assertScores(t, top, aliceMaze, bobClassic)
+
+		if removed, _ := store.Delete(ScoreFilter{PlayerName: "nobody"}); removed != 0 {
+			t.Errorf("removed scores of the unknown player: %d", removed)
+		}
+	})
+}
+
+func TestScoreStoreStats(t *testing.T) {
+	forEachScoreStore(t, testScores, func(t *testing.T, store ScoreStore) {
+		stats, statsError := store.Stats()
+		if statsError != nil {
+			t.Fatal(statsError)
+		}
+		if stats.Count != 4 || stats.PlayerCount != 2 || stats.TotalScore != 160 || stats.AverageScore != 40 {
+			t.Errorf("stats: %+v, expected 4 scores of 2 players totalling 160", stats)
+		}
+		assertScores(t, HighScores{stats.Best}, aliceMaze)
+	})
+}
+
+func TestScoreStoreCompact(t *testing.T) {
+	forEachScoreStore(t, testScores, func(t *testing.T, store ScoreStore) {
+		removed, compactError := store.Compact(RetentionPolicy{TopPerCategory: 1, LastPerPlayer: 1})
+		if compactError != nil {
+			t.Fatal(compactError)
+		}
+		// the best of every category and the latest of every player are kept
+		if removed != 1 {
+			t.Errorf("removed scores: %d, expected: 1", removed)
+		}
+
+		top, topError := store.Top(0, ScoreFilter{})
+		if topError != nil {
+			t.Fatal(topError)
+		}
+		assertScores(t, top, aliceMaze, bobClassic, aliceLatest)
+
+		if removed, _ := store.Compact(RetentionPolicy{}); removed != 0 {
+			t.Errorf("removed scores without the retention: %d", removed)
+		}
+	})
+}
+
+func TestScoreStoreImport(t *testing.T) {
+	forEachScoreStore(t, HighScores{aliceClassic}, func(t *testing.T, store ScoreStore) {
+		kept, importError := store.Import(HighScores{bobClassic, aliceMaze})
+		if importError != nil {
+			t.Fatal(importError)
+		}
+		if kept != 2 {
+			t.Errorf("kept imported scores: %d, expected: 2", kept)
+		}
+
+		top, topError := store.Top(0, ScoreFilter{})
+		if topError != nil {
+			t.Fatal(topError)
+		}
+		assertScores(t, top, aliceMaze, bobClassic, aliceClassic)
+	})
+}
+
+// legacyHighScore is the high score entry written by the game versions before the game modes
+type legacyHighScore struct {
+	Timestamp  time.Time
+	Score      int
+	PlayerName string
+}
+
+func TestFileScoreStoreDecodesLegacyFile(t *testing.T) {
+	buffer := bytes.Buffer{}
+	legacy := []legacyHighScore{{testScoreTime, 30, "alice"}, {testScoreTime.Add(time.Minute), 50, "bob"}}
+	if encodeError := gob.NewEncoder(&buffer).Encode(legacy); encodeError != nil {
+		t.Fatal(encodeError)
+	}
+	content, encryptError := encrypt(buffer.Bytes())
+	if encryptError != nil {
+		t.Fatal(encryptError)
+	}
+
+	dir := t.TempDir()
+	if writeError := ioutil.WriteFile(filepath.Join(dir, highScoreFilename), content, 0644); writeError != nil {
+		t.Fatal(writeError)
+	}
+	store, _ := NewScoreStore(fileScoreStoreKind, dir, RetentionPolicy{})
+
+	top, topError := store.Top(0, ScoreFilter{})
+	if topError != nil {
+		t.Fatal(topError)
+	}
+	expectedBob := HighScore{Timestamp: legacy[1].Timestamp, Score: 50, PlayerName: "bob"}
+	expectedAlice := HighScore{Timestamp: legacy[0].Timestamp, Score: 30, PlayerName: "alice"}
+	assertScores(t, top, expectedBob, expectedAlice)
+
+	// adding the score converts the legacy file into the append-only format keeping the old scores
+	if addError := store.Add(aliceMaze); addError != nil {
+		t.Fatal(addError)
+	}
+	if !store.(*fileScoreStore).isAppendable() {
+		t.Error("legacy file is not converted to the append-only format")
+	}
+	top, _ = store.Top(0, ScoreFilter{})
+	assertScores(t, top, aliceMaze, expectedBob, expectedAlice)
+}
+
+func TestJSONLinesScoreStoreDecodesLegacyLines(t *testing.T) {
+	dir := t.TempDir()
+	lines := `{"timestamp":"2020-03-01T12:00:00Z","score":30,"playerName":"alice"}
+not a score
+{"timestamp":"2020-03-01T12:01:00Z","score":50,"playerName":"bob","mode":"classic","difficulty":"normal"}
+`
+	if writeError := ioutil.WriteFile(filepath.Join(dir, jsonLinesScoreFilename), []byte(lines), 0644); writeError != nil {
+		t.Fatal(writeError)
+	}
+	store, _ := NewScoreStore(jsonlScoreStoreKind, dir, RetentionPolicy{})
+
+	scores, loadError := store.ForPlayer("alice")
+	if loadError != nil {
+		t.Fatal(loadError)
+	}
+	expected := HighScore{Timestamp: testScoreTime, Score: 30, PlayerName: "alice"}
+	if len(scores) != 1 || !reflect.DeepEqual(scores[0], expected) {
+		t.Errorf("legacy scores: %v, expected: %v", scores, expected)
+	}
+
+	// the malformed line is skipped, not counted
+	if stats, _ := store.Stats(); stats.Count != 2 {
+		t.Errorf("scores counted: %d, expected: 2", stats.Count)
+	}
+}
+
+func TestScoreStoreAddsAfterInterruptedAppend(t *testing.T) {
+	filenames := map[string]string{jsonlScoreStoreKind: jsonLinesScoreFilename, fileScoreStoreKind: highScoreFilename}
+	for kind, filename := range filenames {
+		t.Run(kind, func(t *testing.T) {
+			dir := t.TempDir()
+			store, _ := NewScoreStore(kind, dir, RetentionPolicy{})
+			for _, score := range (HighScores{aliceClassic, bobClassic}) {
+				if addError := store.Add(score); addError != nil {
+					t.Fatal(addError)
+				}
+			}
+
+			// the crash during the append leaves the last score cut off
+			path := filepath.Join(dir, filename)
+			if truncateError := os.Truncate(path, fileSize(path)-5); truncateError != nil {
+				t.Fatal(truncateError)
+			}
+			for _, score := range (HighScores{aliceMaze, aliceLatest}) {
+				if addError := store.Add(score); addError != nil {
+					t.Fatal(addError)
+				}
+			}
+
+			top, topError := store.Top(0, ScoreFilter{})
+			if topError != nil {
+				t.Fatal(topError)
+			}
+			assertScores(t, top, aliceMaze, aliceClassic, aliceLatest)
+		})
+	}
+}
+
+func TestRetentionRanksLegacyScoresWithClassicOnes(t *testing.T) {
+	legacy := HighScore{Timestamp: testScoreTime, Score: 90, PlayerName: "carol"}
+	if category := scoreCategory(&legacy); category != scoreCategory(&bobClassic) {
+		t.Errorf("legacy score category: %q, expected: %q", category, scoreCategory(&bobClassic))
+	}
+
+	kept := RetentionPolicy{TopPerCategory: 1, LastPerPlayer: 1}.Apply(HighScores{legacy, aliceClassic, aliceLatest})
+	// the legacy score is the best classic one, so only the latest score of alice is kept besides it
+	assertScores(t, kept, legacy, aliceLatest)
+}
+
+func TestScoreRankCountsLegacyScoresAsClassic(t *testing.T) {
+	store := newMemoryScoreStore()
+	legacy := HighScore{Timestamp: testScoreTime, Score: 90, PlayerName: "carol"}
+	for _, score := range (HighScores{legacy, bobClassic, aliceMaze}) {
+		store.Add(score)
+	}
+
+	top, _ := store.Top(0, ScoreFilter{Mode: classicGameMode})
+	assertScores(t, top, legacy, bobClassic)
+	if rank := scoreRank(store, aliceClassic); rank != 3 {
+		t.Errorf("rank of the classic score: %d, expected: 3", rank)
+	}
+}
+
+func TestRetentionAppliesEachLimitSeparately(t *testing.T) {
+	scores := HighScores{aliceClassic, bobClassic, aliceMaze, aliceLatest}
+
+	kept := RetentionPolicy{TopPerCategory: 1}.Apply(scores)
+	assertScores(t, kept, bobClassic, aliceMaze)
+
+	kept = RetentionPolicy{LastPerPlayer: 1}.Apply(scores)
+	assertScores(t, kept, bobClassic, aliceLatest)
+
+	kept = RetentionPolicy{}.Apply(scores)
+	assertScores(t, kept, scores...)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Scoring model
+//
+// Every eaten food is worth scorePointValue base points, multiplied by the food kind multiplier
+// (see foodScoreMultipliers). The scoring strategy selected in the config adds its own rules on top:
+//
+//	classic     - the base points plus one point per lengthBonusStep tiles of the snake length
+//	combo       - eating the next food within comboWindow ticks raises the combo multiplier by one,
+//	              up to maxComboMultiplier. Missing the window resets it back to 1
+//	time-attack - the game lasts timeAttackDuration ticks. Food eaten within quickBonusWindow ticks
+//	              after the previous one gives an extra point for every second left in the window
+const (
+	classicScoringMode    = classicGameMode
+	comboScoringMode      = "combo"
+	timeAttackScoringMode = "time-attack"
+)
+
+const (
+	lengthBonusStep    = 10
+	comboWindow        = 5 * speedFactor
+	maxComboMultiplier = 5
+	timeAttackDuration = 120 * speedFactor
+	quickBonusWindow   = 10 * speedFactor
+)
+
+// ScoringStrategy calculates the points awarded for the eaten food
+type ScoringStrategy interface {
+	// Name is the identifier of the strategy, stored as the mode of the high score
+	Name() string
+	// State returns the state of the strategy in the current game, stored in the saved games
+	State() map[string]int
+	// Restore sets the state of the strategy from the saved game
+	Restore(state map[string]int)
+	// FoodEaten returns the points awarded for the food eaten event
+	FoodEaten(event gameEvent) ScoreAward
+	// Multiplier returns the strategy multiplier active at the specified tick
+	Multiplier(tick int) int
+	// TimeLimit returns the game duration in ticks, 0 if the game is not limited in time
+	TimeLimit() int
+}
+
+// scoreFactor multiplies the base points of the award
+type scoreFactor struct {
+	name  string
+	value int
+}
+
+// scoreBonus is added to the award after the base points are multiplied
+type scoreBonus struct {
+	name   string
+	points int
+}
+
+// ScoreAward is the amount of points awarded for the eaten food together with the breakdown of its calculation
+type ScoreAward struct {
+	Base    int
+	factors []scoreFactor
+	bonuses []scoreBonus
+}
+
+func newScoreAward(foodKind string) ScoreAward {
+	award := ScoreAward{Base: scorePointValue}
+	award.multiply(foodKind, foodScoreMultipliers[foodKind])
+	return award
+}
+
+func (award *ScoreAward) multiply(name string, value int) {
+	award.factors = append(award.factors, scoreFactor{name, value})
+}
+
+func (award *ScoreAward) add(name string, points int) {
+	if points > 0 {
+		award.bonuses = append(award.bonuses, scoreBonus{name, points})
+	}
+}
+
+// Total calculates the amount of awarded points
+func (award ScoreAward) Total() int {
+	total := award.Base
+	for _, factor := range award.factors {
+		total *= factor.value
+	}
+	for _, bonus := range award.bonuses {
+		total += bonus.points
+	}
+	return total
+}
+
+// String formats the award with its breakdown, like "+64 = 10 x3 bonus x2 combo +4 length"
+func (award ScoreAward) String() string {
+	return fmt.Sprintf("+%d = %s", award.Total(), award.Breakdown())
+}
+
+// Breakdown formats the calculation of the awarded points without their total, like "10 x3 bonus x2 combo +4 length"
+func (award ScoreAward) Breakdown() string {
+	parts := []string{fmt.Sprint(award.Base)}
+	for _, factor := range award.factors {
+		if factor.value != 1 {
+			parts = append(parts, fmt.Sprintf("x%d %s", factor.value, factor.name))
+		}
+	}
+	for _, bonus := range award.bonuses {
+		parts = append(parts, fmt.Sprintf("+%d %s", bonus.points, bonus.name))
+	}
+	return strings.Join(parts, " ")
+}
+
+var scoringStrategies = map[string]func() ScoringStrategy{
+	classicScoringMode:    func() ScoringStrategy { return &classicScoring{} },
+	comboScoringMode:      func() ScoringStrategy { return &comboScoring{} },
+	timeAttackScoringMode: func() ScoringStrategy { return &timeAttackScoring{} }}
+
+var scoring ScoringStrategy = &classicScoring{}
+
+// NewScoringStrategy creates the scoring strategy by its name, falling back to the classic one if it is unknown
+func NewScoringStrategy(name string) ScoringStrategy {
+	create, exists := scoringStrategies[name]
+	if !exists {
+		log.Printf("Unknown scoring mode %q, using %s", name, classicScoringMode)
+		create = scoringStrategies[classicScoringMode]
+	}
+	return create()
+}
+
+type classicScoring struct{}
+
+func (strategy *classicScoring) Name() string { return classicScoringMode }
+
+func (strategy *classicScoring) State() map[string]int { return nil }
+
+func (strategy *classicScoring) Restore(state map[string]int) {}
+
+func (strategy *classicScoring) FoodEaten(event gameEvent) ScoreAward {
+	award := newScoreAward(event.foodKind)
+	award.add("length", event.length/lengthBonusStep)
+	return award
+}
+
+func (strategy *classicScoring) Multiplier(tick int) int { return 1 }
+
+func (strategy *classicScoring) TimeLimit() int { return 0 }
+
+type comboScoring struct {
+	combo        int
+	lastFoodTick int
+}
+
+func (strategy *comboScoring) Name() string { return comboScoringMode }
+
+func (strategy *comboScoring) State() map[string]int {
+	return map[string]int{"combo": strategy.combo, "lastFoodTick": strategy.lastFoodTick}
+}
+
+func (strategy *comboScoring) Restore(state map[string]int) {
+	strategy.combo = state["combo"]
+	strategy.lastFoodTick = state["lastFoodTick"]
+}
+
+func (strategy *comboScoring) FoodEaten(event gameEvent) ScoreAward {
+	if strategy.combo > 0 && event.tick-strategy.lastFoodTick <= comboWindow {
+		strategy.combo = minInt(strategy.combo+1, maxComboMultiplier)
+	} else {
+		strategy.combo = 1
+	}
+	strategy.lastFoodTick = event.tick
+
+	award := newScoreAward(event.foodKind)
+	award.multiply("combo", strategy.combo)
+	return award
+}
+
+// Multiplier returns the combo multiplier, which expires once the combo window has passed since the last food
+func (strategy *comboScoring) Multiplier(tick int) int {
+	if strategy.combo == 0 || tick-strategy.lastFoodTick > comboWindow {
+		return 1
+	}
+	return strategy.combo
+}
+
+func (strategy *comboScoring) TimeLimit() int { return 0 }
+
+type timeAttackScoring struct {
+	lastFoodTick int
+}
+
+func (strategy *timeAttackScoring) Name() string { return timeAttackScoringMode }
+
+func (strategy *timeAttackScoring) State() map[string]int {
+	return map[string]int{"lastFoodTick": strategy.lastFoodTick}
+}
+
+func (strategy *timeAttackScoring) Restore(state map[string]int) {
+	strategy.lastFoodTick = state["lastFoodTick"]
+}
+
+func (strategy *timeAttackScoring) FoodEaten(event gameEvent) ScoreAward {
+	award := newScoreAward(event.foodKind)
+	award.add("quick", (quickBonusWindow-(event.tick-strategy.lastFoodTick))/speedFactor)
+	strategy.lastFoodTick = event.tick
+	return award
+}
+
+func (strategy *timeAttackScoring) Multiplier(tick int) int { return 1 }
+
+func (strategy *timeAttackScoring) TimeLimit() int { return timeAttackDuration }
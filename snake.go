@@ -4,10 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"math"
 	"math/rand"
 	"os"
-	"sort"
 	"strconv"
 	"time"
 
@@ -16,26 +14,59 @@ import (
 
 //======================= texture :) definitions =======================
 
+// the glyphs of the snake, the food and the walls are defined by the current theme
+const emptyTexture = ` `
+
+var foodAnimation = NewAnimation(builtinThemes[0].Glyphs.Food, 1)
+var bonusFoodAnimation = NewAnimation(builtinThemes[0].Glyphs.BonusFood, 2)
+
 const (
-	headTexture  = `#`
-	tailTexture  = `o`
-	emptyTexture = ` `
+	regularFoodKind = "regular"
+	bonusFoodKind   = "bonus"
 )
 
-var foodAnimation = NewAnimation([]string{`-`, `\`, `|`, `/`}, 1)
+// one of bonusFoodChance generated food is the bonus one
+const bonusFoodChance = 6
+
+// score multipliers of the food kinds
+var foodScoreMultipliers = map[string]int{
+	regularFoodKind: 1,
+	bonusFoodKind:   3}
 
 //======================= event definitions =======================
 
 const (
-	collisionEvent = "collision"
-	exitEvent      = "exit"
-	foodEatenEvent = "foodEaten"
-	newGameEvent   = "newGame"
-	helpEvent      = "help"
-	highScoreEvent = "highScore"
-	aboutEvent     = "about"
+	collisionEvent    = "collision"
+	exitEvent         = "exit"
+	foodEatenEvent    = "foodEaten"
+	newGameEvent      = "newGame"
+	helpEvent         = "help"
+	highScoreEvent    = "highScore"
+	statisticsEvent   = "statistics"
+	achievementsEvent = "achievements"
+	aboutEvent        = "about"
+	themeEvent        = "theme"
+	forgetNameEvent   = "forgetName"
+	tickEvent         = "tick"
+	turnEvent         = "turn"
+	gameStartedEvent  = "gameStarted"
+	loadGameEvent     = "loadGame"
+	saveGameEvent     = "saveGame"
 )
 
+// gameEvent is an occurrence in the game, handled by handleEvents and passed to the event listeners afterwards
+type gameEvent struct {
+	name     string
+	foodKind string
+	length   int
+	tick     int
+}
+
+// gameEventListener reacts on the game events after they are handled by the game itself
+type gameEventListener interface {
+	handleGameEvent(event gameEvent)
+}
+
 //======================= direction definitions =======================
 
 var (
@@ -50,9 +81,11 @@ var (
 
 var (
 	objects     = make([]object, 0)
-	events      = make(chan string, 1)
+	events      = make(chan gameEvent, 16)
 	currentFood = &food{}
 	playerSnake = &snake{}
+
+	eventListeners = []gameEventListener{achievementProgress}
 )
 
 //======================= window definitions =======================
@@ -64,43 +97,81 @@ var (
 	statsY = 0
 	statsH = 0
 	statsW = 0
+
+	statsWindow *gc.Window
 )
 
 //========================= Gameplay definitions =========================
 
-//once it is false - game is over :(
-var isRunning = true
+var score = 0
 
-//Main menu is shown during isPaused = true
-var isPaused = false
+// name of the player who saved the latest score during this session
+var lastPlayerName = ""
 
-var score = 0
-var boundFactor = 0
+// Current game session statistics
+var (
+	deathCause = ""
+	gameTicks  = 0
+	gameSeed   int64
+	gameRandom = rand.New(rand.NewSource(0))
+	// gameRandomSource is the source of gameRandom, its state is stored in the saved games
+	gameRandomSource = newCountingSource(0, 0)
+	foodEaten        = make(map[string]int)
+)
 
-const scorePointValue = 6
+// scorePointValue is the amount of base points for the eaten food, see scoring.go for the scoring model
+const scorePointValue = 10
 const speedFactor = 8
 const initialLength = 4
+const tickDuration = time.Second / speedFactor
 
-//======================= Main menu definitions =======================
+const gameVersion = "1.1.0"
+const classicGameMode = "classic"
+const normalDifficulty = "normal"
+
+const (
+	wallDeathCause = "wall"
+	selfDeathCause = "self"
+	quitDeathCause = "quit"
+	timeDeathCause = "time"
+)
 
-var menu = &MenuWindow{}
+//======================= Main menu definitions =======================
 
 const (
-	continueMenuItemTitle  = "Continue"
-	newmenuItemTitle       = "New Game"
-	optionsMenuItemTitle   = "Help"
-	highScoreMenuItemTitle = "High Score"
-	aboutMenuItemTitle     = "About"
-	exitMenuItemTitle      = "Exit"
+	continueMenuItemTitle     = "Continue"
+	newmenuItemTitle          = "New Game"
+	loadGameMenuItemTitle     = "Load Game"
+	optionsMenuItemTitle      = "Help"
+	recordsMenuItemTitle      = "Records"
+	highScoreMenuItemTitle    = "High Score"
+	statisticsMenuItemTitle   = "Statistics"
+	achievementsMenuItemTitle = "Achievements"
+	settingsMenuItemTitle     = "Settings"
+	themeMenuItemTitle        = "Theme"
+	countdownMenuItemTitle    = "Countdown"
+	forgetNameMenuItemTitle   = "Forget my name"
+	aboutMenuItemTitle        = "About"
+	saveGameMenuItemTitle     = "Save & Quit"
+	exitMenuItemTitle         = "Exit"
 )
 
 const (
-	continueMenuItemDescription  = " -- Resume current game"
-	newmenuItemDescription       = " -- Begin new game"
-	optionsMenuItemDescription   = " -- See the gameplay help"
-	highScoreMenuItemDescription = " -- See the leadership table"
-	aboutMenuItemDescription     = " -- Info about creator"
-	exitMenuItemDescription      = " -- Save score and close the game"
+	continueMenuItemDescription     = " -- Resume current game"
+	newmenuItemDescription          = " -- Begin new game"
+	loadGameMenuItemDescription     = " -- Resume the saved game"
+	optionsMenuItemDescription      = " -- See the gameplay help"
+	recordsMenuItemDescription      = " -- Scores, statistics, achievements"
+	highScoreMenuItemDescription    = " -- See the leadership table"
+	statisticsMenuItemDescription   = " -- See the players lifetime statistics"
+	achievementsMenuItemDescription = " -- See the unlocked achievements"
+	settingsMenuItemDescription     = " -- Adjust the game"
+	themeMenuItemDescription        = " -- Choose the glyphs and the colors"
+	countdownMenuItemDescription    = " -- Before the game starts"
+	forgetNameMenuItemDescription   = " -- Ask the player name after every game"
+	aboutMenuItemDescription        = " -- Info about creator"
+	saveGameMenuItemDescription     = " -- Save the game to resume it later"
+	exitMenuItemDescription         = " -- Save score and close the game"
 )
 
 var menuOptionsKeySet = []*MenuItem{
@@ -114,21 +185,59 @@ var menuOptionsKeySet = []*MenuItem{
 		MenuItemDescription: newmenuItemDescription,
 		MenuItemHandler:     newGameOptionHandler},
 
+	&MenuItem{
+		MenuItemTitle:       loadGameMenuItemTitle,
+		MenuItemDescription: loadGameMenuItemDescription,
+		MenuItemHandler:     loadGameOptionHandler,
+		MenuItemEnabled:     hasSavedGames},
+
 	&MenuItem{
 		MenuItemTitle:       optionsMenuItemTitle,
 		MenuItemDescription: optionsMenuItemDescription,
 		MenuItemHandler:     helpOptionHandler},
 
-	&MenuItem{
-		MenuItemTitle:       highScoreMenuItemTitle,
-		MenuItemDescription: highScoreMenuItemDescription,
-		MenuItemHandler:     highScoreOptionHandler},
+	NewSubmenuItem(recordsMenuItemTitle, recordsMenuItemDescription, []*MenuItem{
+		&MenuItem{
+			MenuItemTitle:       highScoreMenuItemTitle,
+			MenuItemDescription: highScoreMenuItemDescription,
+			MenuItemHandler:     highScoreOptionHandler},
+
+		&MenuItem{
+			MenuItemTitle:       statisticsMenuItemTitle,
+			MenuItemDescription: statisticsMenuItemDescription,
+			MenuItemHandler:     statisticsOptionHandler},
+
+		&MenuItem{
+			MenuItemTitle:       achievementsMenuItemTitle,
+			MenuItemDescription: achievementsMenuItemDescription,
+			MenuItemHandler:     achievementsOptionHandler}}),
+
+	NewSubmenuItem(settingsMenuItemTitle, settingsMenuItemDescription, []*MenuItem{
+		&MenuItem{
+			MenuItemTitle:       themeMenuItemTitle,
+			MenuItemDescription: themeMenuItemDescription,
+			MenuItemHandler:     themeOptionHandler},
+		&MenuItem{
+			MenuItemTitle:       countdownMenuItemTitle,
+			MenuItemDescription: countdownMenuItemDescription,
+			MenuItemHandler:     countdownOptionHandler,
+			MenuItemState:       countdownSetting},
+		&MenuItem{
+			MenuItemTitle:       forgetNameMenuItemTitle,
+			MenuItemDescription: forgetNameMenuItemDescription,
+			MenuItemHandler:     forgetNameOptionHandler,
+			MenuItemEnabled:     func() bool { return gameConfig.RememberPlayerName }}}),
 
 	&MenuItem{
 		MenuItemTitle:       aboutMenuItemTitle,
 		MenuItemDescription: aboutMenuItemDescription,
 		MenuItemHandler:     aboutOptionHandler},
 
+	&MenuItem{
+		MenuItemTitle:       saveGameMenuItemTitle,
+		MenuItemDescription: saveGameMenuItemDescription,
+		MenuItemHandler:     saveGameOptionHandler},
+
 	&MenuItem{
 		MenuItemTitle:       exitMenuItemTitle,
 		MenuItemDescription: exitMenuItemDescription,
@@ -145,16 +254,15 @@ type object interface {
 }
 
 type snake struct {
-	head        *Node
-	body        *LinkedList
-	direction   *point
-	headTexture string
-	tailTexture string
+	head      *Node
+	body      *LinkedList
+	direction *point
 }
 
 type food struct {
 	position  *point
 	animation Animation
+	kind      string
 }
 
 //=====================================================
@@ -178,15 +286,17 @@ func (s *snake) update(w *gc.Window) {
 	dx := s.head.Data.(point).x + s.direction.x
 	newHead := &Node{Data: point{dy, dx}}
 
-	if s.checkCollision(newHead) {
-		events <- collisionEvent
+	if cause := s.collisionCause(newHead); cause != "" {
+		deathCause = cause
+		emitEvent(gameEvent{name: collisionEvent})
 	}
 
 	if s.checkFoodCollision(newHead) {
+		eatenFoodKind := currentFood.kind
 		s.body.Prepend(&Node{Data: point{dy, dx}})
 		newHead.Data.(point).offsetP(s.direction)
 		*currentFood = *generateFood(s)
-		events <- foodEatenEvent
+		emitEvent(gameEvent{name: foodEatenEvent, foodKind: eatenFoodKind})
 	}
 
 	last := s.body.Back()
@@ -196,23 +306,66 @@ func (s *snake) update(w *gc.Window) {
 	s.head = newHead
 }
 
+// draw prints the body segments shaped by their neighbours, the tail tip and the head looking in the moving direction
 func (s *snake) draw(w *gc.Window) {
-	w.ColorOn(2)
-	w.AttrOn(gc.A_BOLD)
-	w.MovePrint(s.head.Data.(point).y, s.head.Data.(point).x, s.headTexture)
-	for node := s.head.Next; node.Next != nil; node = node.Next {
-		w.MovePrint(node.Data.(point).y, node.Data.(point).x, s.tailTexture)
+	glyphs := themeGlyphs()
+	segments := s.segments()
+	// the head is drawn last, since the segment added with the eaten food shares its position
+	for idx := len(segments) - 1; idx >= 0; idx-- {
+		segment := segments[idx]
+		pair, attribute := snakeSegmentStyle(idx, len(segments))
+		w.ColorOn(pair)
+		w.AttrOn(attribute)
+		switch idx {
+		case 0:
+			w.MovePrint(segment.y, segment.x, glyphs.headGlyph(s.direction))
+		case len(segments) - 1:
+			w.MovePrint(segment.y, segment.x, glyphs.tailGlyph())
+		default:
+			toHead := point{segments[idx-1].y - segment.y, segments[idx-1].x - segment.x}
+			toTail := point{segments[idx+1].y - segment.y, segments[idx+1].x - segment.x}
+			glyph, lineChar := glyphs.segmentGlyph(toHead, toTail)
+			drawGlyph(w, segment.y, segment.x, glyph, lineChar)
+		}
+		w.AttrOff(attribute)
+		w.ColorOff(pair)
 	}
-	w.AttrOff(gc.A_BOLD)
-	w.ColorOff(2)
+}
+
+// segments returns the positions of the snake from the head to the tail.
+// The node added when the food is eaten shares the position with the head, so the repeated positions are skipped
+func (s *snake) segments() []point {
+	segments := []point{}
+	for node := s.head; node != nil; node = node.Next {
+		position := node.Data.(point)
+		if len(segments) == 0 || segments[len(segments)-1] != position {
+			segments = append(segments, position)
+		}
+	}
+	return segments
+}
+
+// boardSize returns the width and height of the game window the snake moves in, including its border
+func boardSize() (int, int) {
+	return statsW - 2, maxY - statsH
 }
 
 func (s *snake) checkCollision(n *Node) bool {
-	return n.Data.(point).x <= 0 ||
+	return s.collisionCause(n) != ""
+}
+
+// collisionCause returns the death cause if the node collides with the wall or the snake body, empty string otherwise
+func (s *snake) collisionCause(n *Node) string {
+	if n.Data.(point).x <= 0 ||
 		n.Data.(point).y <= 0 ||
 		n.Data.(point).x >= maxX-3 ||
-		n.Data.(point).y >= maxY-statsH-1 ||
-		s.body.Contains(n)
+		n.Data.(point).y >= maxY-statsH-1 {
+		return wallDeathCause
+	}
+	if s.body.Contains(n) {
+		return selfDeathCause
+	}
+	return ""
 }
 
 func (s *snake) checkFoodCollision(n *Node) bool {
@@ -233,11 +386,19 @@ func (f *food) update(w *gc.Window) {
 }
 
 func (f *food) draw(w *gc.Window) {
-	w.ColorOn(1)
-	w.AttrOn(gc.A_BOLD)
+	color, attribute := foodGlowStyle(f.kind, gameTicks)
+	w.ColorOn(color)
+	w.AttrOn(attribute)
 	w.MovePrint(f.position.y, f.position.x, f.animation.CurrentFrame())
-	w.AttrOff(gc.A_BOLD)
-	w.ColorOff(1)
+	w.AttrOff(attribute)
+	w.ColorOff(color)
+}
+
+// redrawGame draws the whole game field again, like after the theme is changed
+func redrawGame(w *gc.Window) {
+	w.Erase()
+	drawWalls(w)
+	drawObjects(w)
 }
 
 func drawObjects(s *gc.Window) {
@@ -253,16 +414,24 @@ func updateObjects(w *gc.Window) {
 }
 
 func tick(w *gc.Window) {
+	gameTicks++
+	emitEvent(gameEvent{name: tickEvent})
 	updateObjects(w)
+	effects.Update(w)
 	drawObjects(w)
 	w.Refresh()
 }
 
-func handleInput(w *gc.Window, s *snake) {
-	key := byte(w.GetChar())
-	if key == 0 {
-		return
-	}
+// handleInput changes the snake direction or pauses the game on the key pressed by the player
+func handleInput(input gc.Key, w *gc.Window, s *snake) SceneTransition {
+	key := byte(input)
+	previousDirection := s.direction
+	defer func() {
+		if s.direction != previousDirection {
+			currentReplay.Record(gameTicks, s.direction)
+			emitEvent(gameEvent{name: turnEvent})
+		}
+	}()
 
 	switch key {
 	case 'w':
@@ -286,55 +455,57 @@ func handleInput(w *gc.Window, s *snake) {
 		}
 		break
 	case 'p', 27: // p or escape key
-		pause(w)
-		break
+		return pause(w)
 	default:
 		break
 	}
+	return stay()
 }
 
-func pause(w *gc.Window) {
-	isPaused = !isPaused
-	if isPaused {
-		menu = createMenu(w).(*MenuWindow)
-	}
+// pause shows the main menu over the game
+func pause(w *gc.Window) SceneTransition {
+	toasts.Clear(w)
+	scorePopups.Clear(w)
+	return pushScene(newPausedScene(w))
 }
 
 func createMenu(w *gc.Window) Menu {
 	return NewMenu(w, menuOptionsKeySet)
 }
 
-func gameOver(s *gc.Window) {
-	lines, cols := s.MaxYX()
-	msg := "Game Over"
-
-	wnd, err := createWindow(5, len(msg)+4, (lines/2)-2, (cols-len(msg))/2)
-	if err != nil {
-		log.Panic("Error creating game over window", err)
-		return
-	}
-
-	wnd.MovePrint(2, 2, msg)
-	wnd.Box(gc.ACS_VLINE, gc.ACS_HLINE)
-	wnd.Refresh()
-	gc.Nap(2000)
+// gameOver saves the result of the finished game and shows the game over window
+func gameOver(w *gc.Window) SceneTransition {
+	return saveGame(w, func(result HighScore) Scene {
+		return newGameOverScene(w, result)
+	})
 }
 
 func drawStats(sn *snake) {
 	snakeLength := "length: " + strconv.Itoa(sn.body.Size())
 	scoredPoints := "score: " + strconv.Itoa(score)
+	multiplier := "multiplier: x" + strconv.Itoa(scoring.Multiplier(gameTicks))
 
-	wnd, err := createWindow(statsH, statsW-2, statsY, statsX)
-	if err != nil {
-		log.Panic("Error creating stats window", err)
-		return
+	if statsWindow == nil {
+		wnd, err := createWindow(statsH, statsW-2, statsY, statsX)
+		if err != nil {
+			log.Panic("Error creating stats window", err)
+			return
+		}
+		statsWindow = wnd
 	}
 
-	wnd.ColorOn(3)
+	wnd := statsWindow
+	wnd.Erase()
+	wnd.ColorOn(textColorPair)
 	wnd.AttrOn(gc.A_BOLD)
 	wnd.MovePrint(1, 1, snakeLength)
 	wnd.MovePrint(1, len(snakeLength)+3, scoredPoints)
-	wnd.ColorOff(3)
+	wnd.MovePrint(1, len(snakeLength)+len(scoredPoints)+5, multiplier)
+	if timeLimit := scoring.TimeLimit(); timeLimit > 0 {
+		timeLeft := "time: " + formatDuration(time.Duration(timeLimit-gameTicks)*tickDuration)
+		wnd.MovePrint(1, len(snakeLength)+len(scoredPoints)+len(multiplier)+7, timeLeft)
+	}
+	wnd.ColorOff(textColorPair)
 	wnd.AttrOff(gc.A_BOLD)
 	wnd.Box(gc.ACS_VLINE, gc.ACS_HLINE)
 	wnd.Refresh()
@@ -350,18 +521,21 @@ func createSnake(y, x int) *snake {
 
 	body.Prepend(head)
 
-	newSnake := &snake{head, body, left, headTexture, tailTexture}
+	newSnake := &snake{head, body, left}
 	return newSnake
 }
 
 func generateFood(sn *snake) *food {
-	randX := 1 + rand.Intn(maxX-4)
-	randY := 1 + rand.Intn(maxY-statsH-2)
+	randX := 1 + gameRandom.Intn(maxX-4)
+	randY := 1 + gameRandom.Intn(maxY-statsH-2)
 	foodPos := &point{y: randY, x: randX}
 	if sn.containsNodeWithPoint(foodPos) {
-		generateFood(sn)
+		return generateFood(sn)
+	}
+	if gameRandom.Intn(bonusFoodChance) == 0 {
+		return &food{foodPos, foodAnimationOf(bonusFoodKind), bonusFoodKind}
 	}
-	return &food{foodPos, foodAnimation}
+	return &food{foodPos, foodAnimationOf(regularFoodKind), regularFoodKind}
 }
 
 func createWindow(height, width, y, x int) (*gc.Window, error) {
@@ -385,7 +559,7 @@ func createGameWindow(y, x, height, width int) (*gc.Window, error) {
 		log.Panic("Error creating game window:", err)
 		return nil, err
 	}
-	wnd.Box(gc.ACS_VLINE, gc.ACS_HLINE)
+	drawWalls(wnd)
 	wnd.Refresh()
 	return wnd, nil
 }
@@ -399,53 +573,131 @@ func openLogFile() *os.File {
 }
 
 func newGame(w *gc.Window, headY int, headX int) {
-	log.Print("Starting new game...")
+	gameSeed = time.Now().UnixNano()
+	gameRandomSource = newCountingSource(gameSeed, 0)
+	gameRandom = rand.New(gameRandomSource)
+	gameTicks = 0
+	deathCause = ""
+	foodEaten = make(map[string]int)
+	log.Printf("Starting new game with seed %d...", gameSeed)
+	boardWidth, boardHeight := boardSize()
+	currentReplay = NewReplay(gameSeed, boardWidth, boardHeight)
 	playerSnake = createSnake(headY, headX)
 	currentFood = generateFood(playerSnake)
 	objects = make([]object, 0)
 	objects = append(objects, playerSnake, currentFood)
 	score = 0
+	scoring = NewScoringStrategy(gameConfig.ScoringMode)
+	emitEvent(gameEvent{name: gameStartedEvent})
+	effects.Clear(w)
 	w.Erase()
-	w.Box(gc.ACS_VLINE, gc.ACS_HLINE)
+	drawWalls(w)
 	w.Refresh()
 }
 
-func handleEvents(s *snake, w *gc.Window) {
-	select {
-	case event := <-events:
-		log.Printf("Event occurred: %s", event)
-		switch event {
-		case foodEatenEvent:
-			incrementScore(s)
-			log.Printf("Score increased. Current score: %d", score)
-			break
-		case collisionEvent:
-			isRunning = false
-			break
-		case exitEvent:
-			isRunning = false
-			break
-		case newGameEvent:
-			newGame(w, maxY/2, maxX/2)
-			break
-		case highScoreEvent:
-			createHighScoreWindow(w)
-			break
-		case aboutEvent:
-			createAboutWindow(w)
-			break
-		case helpEvent:
-			createHelpWindow(w)
-			break
+// emitEvent queues the event, filling in the current game state
+func emitEvent(event gameEvent) {
+	event.tick = gameTicks
+	event.length = playerSnake.body.Size()
+	events <- event
+}
+
+// emitMenuEvent queues the event requested from the main menu
+func emitMenuEvent(name string) {
+	events <- gameEvent{name: name}
+}
+
+// handleEvents processes all of the queued events. Returns the first scene transition requested by the events
+func handleEvents(s *snake, w *gc.Window) SceneTransition {
+	transition := stay()
+	for {
+		select {
+		case event := <-events:
+			eventTransition := handleEvent(event, s, w)
+			if transition.kind == stayTransition {
+				transition = eventTransition
+			}
 		default:
-			break
+			return transition
+		}
+	}
+}
+
+func handleEvent(event gameEvent, s *snake, w *gc.Window) SceneTransition {
+	transition := stay()
+	if event.name != tickEvent {
+		log.Printf("Event occurred: %s", event.name)
+	}
+
+	switch event.name {
+	case foodEatenEvent:
+		foodEaten[event.foodKind]++
+		incrementScore(event)
+		log.Printf("Score increased. Current score: %d", score)
+		break
+	case tickEvent:
+		if timeLimit := scoring.TimeLimit(); timeLimit > 0 && event.tick >= timeLimit {
+			deathCause = timeDeathCause
+			transition = gameOver(w)
+		}
+		break
+	case collisionEvent:
+		effects.Add(newDeathExplosion(s)...)
+		transition = gameOver(w)
+		break
+	case exitEvent:
+		if deathCause == "" {
+			deathCause = quitDeathCause
 		}
+		transition = exitGame(w)
+		break
+	case newGameEvent:
+		newGame(w, maxY/2, maxX/2)
+		break
+	case loadGameEvent:
+		transition = pushScene(newLoadGameScene(w,
+			func(saved *SavedGame) SceneTransition {
+				restoreGame(w, saved)
+				return popScene()
+			},
+			popScene))
+		break
+	case saveGameEvent:
+		transition = pushScene(createSaveGameWindow(w))
+		break
+	case highScoreEvent:
+		transition = pushScene(createHighScoreWindow(w))
+		break
+	case statisticsEvent:
+		transition = pushScene(createStatisticsWindow(w))
+		break
+	case achievementsEvent:
+		transition = pushScene(createAchievementsWindow(w))
+		break
+	case themeEvent:
+		transition = pushScene(newThemeScene(w, func() { redrawGame(w) }))
+		break
+	case forgetNameEvent:
+		forgetPlayerName()
+		transition = pushScene(createForgetNameWindow(w))
+		break
+	case aboutEvent:
+		transition = pushScene(createAboutWindow(w))
+		break
+	case helpEvent:
+		transition = pushScene(createHelpWindow(w))
+		break
 	default:
 		break
 	}
+
+	for _, listener := range eventListeners {
+		listener.handleGameEvent(event)
+	}
+	return transition
 }
 
-func createAboutWindow(w *gc.Window) {
+func createAboutWindow(w *gc.Window) Scene {
 	const aboutWindowHeight = 8
 	const aboutWindowWidth = 40
 	const aboutWindowTitle = "About"
@@ -456,70 +708,203 @@ func createAboutWindow(w *gc.Window) {
 		"",
 		"Have fun!"}
 
-	showMessageBox(aboutWindowHeight, aboutWindowWidth, aboutWindowTitle, aboutText, w)
+	return showMessageBox(aboutWindowHeight, aboutWindowWidth, aboutWindowTitle, aboutText, w)
 }
 
-func createHelpWindow(w *gc.Window) {
-	const helpWindowHeight = 8
-	const helpWindowWidth = 42
+func createForgetNameWindow(w *gc.Window) Scene {
+	// the window is sized to fit the text
+	const forgetNameWindowHeight = 0
+	const forgetNameWindowWidth = 50
+
+	forgetNameText := []string{"Your name will be asked after the next game."}
+	return showMessageBox(forgetNameWindowHeight, forgetNameWindowWidth, forgetNameMenuItemTitle, forgetNameText, w)
+}
+
+func createHelpWindow(w *gc.Window) Scene {
+	// the help window is sized to fit the text, the width is only limited
+	const helpWindowHeight = 0
+	const helpWindowWidth = 50
 	const helpWindowTitle = "Help"
 
 	var helpText = []string{
 		"Controls:",
 		"'W' 'S' 'A' 'D' for direction change",
-		"'P' or 'Esc' for pause/menu"}
+		"'P' or 'Esc' for pause/menu",
+		"",
+		"Eat the food to grow and score points. The blinking bonus food is worth more, " +
+			"but it disappears soon. Do not crash into the walls or your own tail.",
+		"",
+		"The points awarded for the food depend on the scoring mode chosen in the settings, " +
+			"see the score breakdown shown under the game field after every meal."}
 
-	showMessageBox(helpWindowHeight, helpWindowWidth, helpWindowTitle, helpText, w)
+	return showMessageBox(helpWindowHeight, helpWindowWidth, helpWindowTitle, helpText, w)
 }
 
-func createHighScoreWindow(w *gc.Window) {
-	const highscoreWindowTitle = "High scores"
-	const highScoreWindowWidth = 70
-	const highScoreWindowHeight = 14
-
-	scores, scoreLoadError := LoadHighScore()
+func createHighScoreWindow(w *gc.Window) Scene {
+	scores, scoreLoadError := scoreStore.Top(0, ScoreFilter{})
 	if scoreLoadError != nil {
 		log.Println("Error loading high scores: ", scoreLoadError)
 		scores = HighScores{}
 	}
 
-	sort.Sort(scores)
+	return newDialogScene("highScores", w, NewHighScoreBrowser(scores, lastPlayerName))
+}
 
-	scoreContent := []string{}
-	for idx, score := range scores {
-		if idx >= maxAmountOfTopHighScores {
-			break
-		}
-		scoreContent = append(scoreContent, score.String())
+func createStatisticsWindow(w *gc.Window) Scene {
+	profiles, profilesLoadError := LoadProfiles()
+	if profilesLoadError != nil {
+		log.Println("Error loading player profiles: ", profilesLoadError)
+		profiles = PlayerProfiles{}
+	}
+
+	return newDialogScene("statistics", w, NewStatisticsWindow(profiles, lastPlayerName))
+}
+
+func createAchievementsWindow(w *gc.Window) Scene {
+	achievements, achievementsLoadError := LoadAchievements()
+	if achievementsLoadError != nil {
+		log.Println("Error loading achievements: ", achievementsLoadError)
+		achievements = PlayerAchievements{}
 	}
 
-	showMessageBox(highScoreWindowHeight, highScoreWindowWidth, highscoreWindowTitle, scoreContent, w)
+	return newDialogScene("achievements", w, NewAchievementsWindow(achievements, lastPlayerName))
 }
 
-func showMessageBox(height int, width int, title string, text []string, w *gc.Window) {
-	mBox := MessageBox{
+func showMessageBox(height int, width int, title string, text []string, w *gc.Window) Scene {
+	mBox := &MessageBox{
 		Height:      height,
 		Width:       width,
 		Title:       title,
 		MessageText: text}
 
-	mBox.Show(w)
+	return newDialogScene(title, w, mBox)
 }
 
-func incrementScore(s *snake) {
-	score += (scorePointValue*speedFactor + s.body.Size()) - boundFactor
+// showConfirmation asks the Yes/No question. onYes is called once it is confirmed and returns the transition to perform
+func showConfirmation(title string, text []string, w *gc.Window, onYes func() SceneTransition) Scene {
+	mBox := &MessageBox{
+		Title:       title,
+		MessageText: text,
+		Buttons:     yesNoButtons,
+		OnResult: func(result MessageBoxResult) SceneTransition {
+			if result == messageBoxYes {
+				return onYes()
+			}
+			return popScene()
+		}}
+
+	return newDialogScene(title, w, mBox)
 }
 
-// saveHighScore Enter player name and save the high score if it is greater than 0
-func saveHighScore(w *gc.Window) {
+// incrementScore adds the points awarded by the scoring strategy.
+// The points float up from the eaten food, which bursts into the particles,
+// their breakdown is shown at the bottom of the field unless they are just the base points
+func incrementScore(event gameEvent) {
+	award := scoring.FoodEaten(event)
+	score += award.Total()
+	if award.Total() != award.Base {
+		scorePopups.Replace(award.Breakdown())
+	}
+
+	head := playerSnake.head.Data.(point)
+	burstColor := foodColorPair
+	if event.foodKind == bonusFoodKind {
+		burstColor = bonusFoodColorPair
+	}
+	effects.Add(newEatBurst(head, burstColor)...)
+	effects.Add(newScorePopup(head, award.Total()))
+}
+
+// currentGameResult collects the results of the current game session
+func currentGameResult(playerName string) HighScore {
+	boardWidth, boardHeight := boardSize()
+	return HighScore{
+		Timestamp:   time.Now(),
+		Score:       score,
+		PlayerName:  playerName,
+		Length:      playerSnake.body.Size(),
+		Duration:    time.Duration(gameTicks) * tickDuration,
+		Ticks:       gameTicks,
+		FoodEaten:   foodEaten,
+		BoardWidth:  boardWidth,
+		BoardHeight: boardHeight,
+		Mode:        scoring.Name(),
+		Difficulty:  normalDifficulty,
+		Seed:        gameSeed,
+		Version:     gameVersion,
+		DeathCause:  deathCause}
+}
+
+// createSaveGameWindow asks the save slot name, saves the game into it and closes the game
+func createSaveGameWindow(w *gc.Window) Scene {
+	return newTextEntryScene(w, saveGameWindowTitle, saveSlotPrompt, defaultSaveSlot(),
+		func(slot string) SceneTransition {
+			if saveError := SaveGame(slot); saveError != nil {
+				log.Println("Error saving game: ", saveError)
+				toasts.Push("Error saving the game")
+				return popScene()
+			}
+			log.Printf("Game saved to slot %q", slot)
+			return quitGame()
+		},
+		popScene)
+}
+
+// exitGame saves the game in progress and closes the game
+func exitGame(w *gc.Window) SceneTransition {
+	if gameTicks == 0 {
+		return quitGame()
+	}
+	return saveGame(w, func(result HighScore) Scene {
+		return nil
+	})
+}
+
+// saveGame asks the player name if the score is greater than 0 and the name is not remembered, and saves the game result.
+// next returns the scene shown after the result is saved, nil to close the game
+func saveGame(w *gc.Window, next func(result HighScore) Scene) SceneTransition {
+	continueWith := func(result HighScore, show func(Scene) SceneTransition) SceneTransition {
+		if scene := next(result); scene != nil {
+			return show(scene)
+		}
+		return quitGame()
+	}
+
+	if score <= 0 {
+		return continueWith(saveHighScore(lastPlayerName), pushScene)
+	}
+	if gameConfig.RememberPlayerName && gameConfig.PlayerName != "" {
+		lastPlayerName = gameConfig.PlayerName
+		return continueWith(saveHighScore(lastPlayerName), pushScene)
+	}
+	return pushScene(newNameEntryScene(w, func(playerName string) SceneTransition {
+		lastPlayerName = playerName
+		return continueWith(saveHighScore(playerName), replaceScene)
+	}))
+}
+
+// saveHighScore saves the high score if it is greater than 0.
+// The game is recorded in the player profile, if the player is known. Returns the saved game result
+func saveHighScore(playerName string) HighScore {
+	result := currentGameResult(playerName)
+
 	if score > 0 {
-		playerName := GetPlayerName(w)
-		SaveHighScore(
-			&HighScore{
-				Timestamp:  time.Now(),
-				Score:      score,
-				PlayerName: playerName})
+		saveError := scoreStore.Add(result)
+		if saveError != nil {
+			log.Println("Error saving high score: ", saveError)
+		}
+		submitScoreInBackground(result, currentReplay)
+	}
+
+	if playerName != "" {
+		if profileError := RecordGameInProfile(result); profileError != nil {
+			log.Println("Error updating player profile: ", profileError)
+		}
+		if achievementsError := SaveUnlockedAchievements(playerName, achievementProgress.Unlocked()); achievementsError != nil {
+			log.Println("Error saving achievements: ", achievementsError)
+		}
 	}
+	return result
 }
 
 //======================= Main Menu Handlers =======================
@@ -531,31 +916,77 @@ func continueOptionHandler() bool {
 
 func newGameOptionHandler() bool {
 	log.Print("New Game menu option selected")
-	events <- newGameEvent
+	emitMenuEvent(newGameEvent)
+	return false
+}
+
+func loadGameOptionHandler() bool {
+	log.Print("Load Game menu option selected")
+	emitMenuEvent(loadGameEvent)
+	return false
+}
+
+func saveGameOptionHandler() bool {
+	log.Print("Save & Quit menu option selected")
+	emitMenuEvent(saveGameEvent)
 	return false
 }
 
 func helpOptionHandler() bool {
 	log.Print("Help menu option selected")
-	events <- helpEvent
+	emitMenuEvent(helpEvent)
 	return false
 }
 
 func highScoreOptionHandler() bool {
 	log.Print("High Score menu option selected")
-	events <- highScoreEvent
+	emitMenuEvent(highScoreEvent)
+	return false
+}
+
+func statisticsOptionHandler() bool {
+	log.Print("Statistics menu option selected")
+	emitMenuEvent(statisticsEvent)
+	return false
+}
+
+func achievementsOptionHandler() bool {
+	log.Print("Achievements menu option selected")
+	emitMenuEvent(achievementsEvent)
+	return false
+}
+
+func forgetNameOptionHandler() bool {
+	log.Print("Forget my name menu option selected")
+	emitMenuEvent(forgetNameEvent)
+	return false
+}
+
+// countdownOptionHandler switches the countdown to the next of its menu settings, keeping the menu open
+func countdownOptionHandler() bool {
+	gameConfig.Countdown = nextCountdownSetting(gameConfig.Countdown)
+	log.Printf("Countdown is set to %d seconds", gameConfig.Countdown)
+	if saveError := gameConfig.Save(); saveError != nil {
+		log.Println("Error saving config: ", saveError)
+	}
+	return true
+}
+
+func themeOptionHandler() bool {
+	log.Print("Theme menu option selected")
+	emitMenuEvent(themeEvent)
 	return false
 }
 
 func aboutOptionHandler() bool {
 	log.Print("About menu option selected")
-	events <- aboutEvent
+	emitMenuEvent(aboutEvent)
 	return false
 }
 
 func exitOptionHandler() bool {
 	log.Print("Exit menu option selected")
-	events <- exitEvent
+	emitMenuEvent(exitEvent)
 	return false
 }
 
@@ -563,10 +994,10 @@ func exitOptionHandler() bool {
 
 func initNcurses() {
 	// Coloring setup
-	gc.StartColor()
-	gc.InitPair(1, gc.C_RED, gc.C_BLACK)
-	gc.InitPair(2, gc.C_GREEN, gc.C_BLACK)
-	gc.InitPair(3, gc.C_YELLOW, gc.C_BLACK)
+	terminalColors = initColors()
+	applyTheme(findTheme(LoadThemes(), gameConfig.Theme))
+
+	gc.MouseMask(gc.M_B1_CLICKED|gc.M_B4_PRESSED|gc.M_B5_PRESSED, nil)
 
 	gc.Cursor(0)
 	gc.Echo(false)
@@ -587,29 +1018,32 @@ func initScreenDimensions(stdscr *gc.Window) error {
 	return nil
 }
 
+func initScoreStore() {
+	store, storeError := NewScoreStore(gameConfig.ScoreStore, dataDir(), gameConfig.ScoreRetention)
+	if storeError != nil {
+		log.Println("Error initializing high score store, scores will not be persisted: ", storeError)
+		store = newMemoryScoreStore()
+	}
+	scoreStore = store
+}
+
 func initLogging() *os.File {
 	logFile := openLogFile()
 	log.SetOutput(logFile)
 	return logFile
 }
 
-func getScoreBoundFactor() int {
-	factor := int(math.Min(float64(maxX), float64(maxY)))
-	maxFactor := scorePointValue * speedFactor
-	if factor >= maxFactor {
-		log.Println("Screen dimensions are too big -> Adjusting score calculation formula")
-		factor = maxFactor - 1
-	}
-	return factor
-}
-
-func initScoreBoundFactor() {
-	boundFactor = getScoreBoundFactor()
-}
-
 // ==================================================================
 
 func main() {
+	if len(os.Args) > 1 {
+		logFile := initLogging()
+		gameConfig = LoadConfig()
+		exitCode := runCommand(os.Args[1:], os.Stdout, os.Stderr)
+		logFile.Close()
+		os.Exit(exitCode)
+	}
+
 	stdscr, err := gc.Init()
 
 	if err != nil {
@@ -617,17 +1051,24 @@ func main() {
 	}
 
 	stdscr.Keypad(true)
-	rand.Seed(int64(time.Now().Second()))
+	stdscr.Refresh()
 	logFile := initLogging()
 
 	// Finalization
 	defer logFile.Close()
 	defer gc.End()
-	defer gameOver(stdscr)
 	defer log.Println(" <==== Game session ended\n ")
 	//
 
 	log.Println("====> Game session started")
+	gameConfig = LoadConfig()
+	if gameConfig.RememberPlayerName {
+		lastPlayerName = gameConfig.PlayerName
+	}
+	initScoreStore()
+	if cueFile := initCues(); cueFile != nil {
+		defer cueFile.Close()
+	}
 	initNcurses()
 
 	dimensionsInitError := initScreenDimensions(stdscr)
@@ -636,9 +1077,7 @@ func main() {
 		return
 	}
 
-	initScoreBoundFactor()
-
-	ticker := time.NewTicker(time.Second / speedFactor)
+	ticker := time.NewTicker(tickDuration)
 
 	// Create in-game windows
 	gameWindow, err := createGameWindow(statsY+statsH, statsX, maxY-statsH, statsW-2)
@@ -646,29 +1085,21 @@ func main() {
 		log.Panic("Error initializing game window:", err)
 		return
 	}
-	menu = createMenu(gameWindow).(*MenuWindow)
 	//
 
-	newGame(gameWindow, maxY/2, maxX/2)
+	scenes := &SceneStack{}
+	scenes.Apply(pushScene(newTitleScene(gameWindow)))
 
 	// Game Loop:
-	for isRunning {
+	for !scenes.Empty() {
 		select {
 		case <-ticker.C:
-			if !isPaused {
-				handleInput(gameWindow, playerSnake)
-				tick(gameWindow)
-				drawStats(playerSnake)
-				handleEvents(playerSnake, gameWindow)
-			} else {
-				if !menu.HandleInput() {
-					isPaused = false
-					menu.Free()
-					
-				}
-			}
+			// the input is read from the standard screen, which is never redrawn, so the scenes are not overdrawn
+			scenes.HandleKey(stdscr.GetChar())
+			scenes.Update()
+			scenes.Draw()
 		}
 	}
 
-	saveHighScore(stdscr)
+	awaitSubmissions(leaderboardRequestTimeout)
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/VAlux/GSnake/widget"
+	gc "github.com/rthornton128/goncurses"
+)
+
+const (
+	statisticsWindowTitle  = "Statistics"
+	statisticsWindowWidth  = 60
+	statisticsWindowHeight = 19
+	statisticsWindowHelp   = "Left/Right: player  q: close"
+	statisticsNoProfiles   = "No games recorded yet"
+)
+
+// StatisticsWindow shows the lifetime statistics of the player profiles, one player at a time
+type StatisticsWindow struct {
+	profiles    PlayerProfiles
+	names       []string
+	playerIndex int
+	dialog      *widget.Dialog
+	label       *widget.Label
+}
+
+// NewStatisticsWindow creates the statistics window starting with the profile of specified player
+func NewStatisticsWindow(profiles PlayerProfiles, playerName string) *StatisticsWindow {
+	statistics := &StatisticsWindow{
+		profiles: profiles,
+		names:    profiles.SortedNames()}
+
+	for idx, name := range statistics.names {
+		if name == playerName {
+			statistics.playerIndex = idx
+		}
+	}
+	return statistics
+}
+
+// Open creates the window as a child of specified window
+func (statistics *StatisticsWindow) Open(s *gc.Window) error {
+	statistics.label = widget.NewLabel()
+	dialog, dialogError := widget.NewDialog(s, statisticsWindowHeight, statisticsWindowWidth, statisticsWindowTitle, statistics.label)
+	if dialogError != nil {
+		return dialogError
+	}
+
+	dialog.SetFooter(statisticsWindowHelp)
+	statistics.dialog = dialog
+	return nil
+}
+
+// Close removes the window from the screen
+func (statistics *StatisticsWindow) Close() {
+	statistics.dialog.Close()
+}
+
+// HandleKey applies the key action. Returns false if the window should be closed
+func (statistics *StatisticsWindow) HandleKey(key gc.Key) bool {
+	playersAmount := len(statistics.names)
+	switch key {
+	case gc.KEY_LEFT:
+		if playersAmount > 0 {
+			statistics.playerIndex = (statistics.playerIndex + playersAmount - 1) % playersAmount
+		}
+	case gc.KEY_RIGHT:
+		if playersAmount > 0 {
+			statistics.playerIndex = (statistics.playerIndex + 1) % playersAmount
+		}
+	case 'q', escapeKey, gc.KEY_RETURN:
+		return false
+	}
+	return true
+}
+
+// lines returns the content of the window for the currently selected player
+func (statistics *StatisticsWindow) lines() []string {
+	if len(statistics.names) == 0 {
+		return []string{statisticsNoProfiles}
+	}
+
+	profile := statistics.profiles[statistics.names[statistics.playerIndex]]
+	_, width := statistics.dialog.Size()
+	chartWidth := width - 20
+	return []string{
+		fmt.Sprintf("Player: %s  (%d of %d)", profile.Name, statistics.playerIndex+1, len(statistics.names)),
+		"",
+		fmt.Sprintf("Games played:     %d", profile.GamesPlayed),
+		fmt.Sprintf("Average score:    %.1f", profile.AverageScore()),
+		fmt.Sprintf("Best score:       %d", profile.BestScore),
+		fmt.Sprintf("Best length:      %d", profile.BestLength),
+		fmt.Sprintf("Total food:       %d", profile.TotalFood),
+		fmt.Sprintf("Longest survival: %s", formatDuration(profile.LongestSurvival)),
+		fmt.Sprintf("Total play time:  %s", formatDuration(profile.TotalPlayTime)),
+		fmt.Sprintf("Deaths:           %s", formatDeaths(profile.DeathsByCause)),
+		"",
+		fmt.Sprintf("Score trend:  %s", sparkline(lastValues(profile.RecentScores, chartWidth))),
+		fmt.Sprintf("Length trend: %s", sparkline(lastValues(profile.RecentLengths, chartWidth)))}
+}
+
+func lastValues(values []int, amount int) []int {
+	if amount > 0 && len(values) > amount {
+		return values[len(values)-amount:]
+	}
+	return values
+}
+
+// formatDeaths formats the death causes ordered by the amount of deaths
+func formatDeaths(deathsByCause map[string]int) string {
+	if len(deathsByCause) == 0 {
+		return "-"
+	}
+
+	causes := []string{}
+	for cause := range deathsByCause {
+		causes = append(causes, cause)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if deathsByCause[causes[i]] == deathsByCause[causes[j]] {
+			return causes[i] < causes[j]
+		}
+		return deathsByCause[causes[i]] > deathsByCause[causes[j]]
+	})
+
+	parts := []string{}
+	for _, cause := range causes {
+		parts = append(parts, fmt.Sprintf("%s %d", cause, deathsByCause[cause]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (statistics *StatisticsWindow) draw() {
+	statistics.label.SetText(statistics.lines()...)
+	statistics.dialog.Draw()
+}
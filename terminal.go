@@ -0,0 +1,105 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// colorDepth is the amount of colors the game can be drawn with
+type colorDepth int
+
+const (
+	// noColors draws the game with the attributes only
+	noColors colorDepth = iota
+	// basicColors are the 8 colors of the palette
+	basicColors
+	// extendedColors are the 256 colors of xterm, enough for the gradients
+	extendedColors
+	// trueColors are the gradient colors redefined with the exact RGB values
+	trueColors
+)
+
+// noColorVariable disables the colors if it is set to anything, see https://no-color.org
+const noColorVariable = "NO_COLOR"
+const colorTermVariable = "COLORTERM"
+
+// terminalColors is the color depth detected by initColors
+var terminalColors = noColors
+
+// initColors starts the colors if the terminal has them and they are not disabled, and detects their depth
+func initColors() colorDepth {
+	if os.Getenv(noColorVariable) != "" {
+		log.Println("Colors are disabled by " + noColorVariable)
+		return noColors
+	}
+	if !gc.HasColors() {
+		log.Println("Terminal has no colors")
+		return noColors
+	}
+	if startError := gc.StartColor(); startError != nil {
+		log.Println("Error starting colors: ", startError)
+		return noColors
+	}
+
+	var depth colorDepth
+	switch {
+	case gc.Colors() < 256 || gc.ColorPairs() <= int(lastGradientPair):
+		depth = basicColors
+	case trueColorTerminal() && gc.CanChangeColor():
+		depth = trueColors
+	default:
+		depth = extendedColors
+	}
+	log.Printf("Terminal colors: %d, color pairs: %d, depth: %d", gc.Colors(), gc.ColorPairs(), depth)
+	return depth
+}
+
+// trueColorTerminal checks if the terminal declares the support of 24-bit colors
+func trueColorTerminal() bool {
+	colorTerm := strings.ToLower(os.Getenv(colorTermVariable))
+	return colorTerm == "truecolor" || colorTerm == "24bit"
+}
+
+// unicodeSupported checks if the terminal locale is able to show the non-ASCII glyphs
+func unicodeSupported() bool {
+	for _, variable := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(variable); value != "" {
+			value = strings.ToUpper(value)
+			return strings.Contains(value, "UTF-8") || strings.Contains(value, "UTF8")
+		}
+	}
+	return false
+}
+
+var unicodeSparklineLevels = []rune("▁▂▃▄▅▆▇█")
+var asciiSparklineLevels = []rune("_.-=+*#")
+
+// sparkline renders the values as a single line chart, scaled between the minimal and maximal value
+func sparkline(values []int) string {
+	levels := asciiSparklineLevels
+	if unicodeSupported() {
+		levels = unicodeSparklineLevels
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	minValue, maxValue := values[0], values[0]
+	for _, value := range values {
+		minValue = minInt(minValue, value)
+		maxValue = maxInt(maxValue, value)
+	}
+
+	chart := make([]rune, len(values))
+	for idx, value := range values {
+		level := 0
+		if maxValue > minValue {
+			level = (value - minValue) * (len(levels) - 1) / (maxValue - minValue)
+		}
+		chart[idx] = levels[level]
+	}
+	return string(chart)
+}
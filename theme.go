@@ -0,0 +1,666 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/VAlux/GSnake/widget"
+	gc "github.com/rthornton128/goncurses"
+)
+
+const themesDirName = "themes"
+const themeFileExtension = ".json"
+
+const (
+	classicThemeName      = "classic"
+	unicodeThemeName      = "unicode"
+	highContrastThemeName = "high-contrast"
+	monochromeThemeName   = "monochrome"
+	deuteranopiaThemeName = "deuteranopia"
+	tritanopiaThemeName   = "tritanopia"
+)
+
+// Color pairs initialized from the palette of the current theme
+const (
+	titleColorPair     int16 = 1
+	snakeColorPair     int16 = 2
+	textColorPair      int16 = 3
+	foodColorPair      int16 = 4
+	bonusFoodColorPair int16 = 5
+	wallColorPair      int16 = 6
+)
+
+// Color pairs of the gradients, initialized only if the terminal has 256 colors.
+// On the true color terminal the colors of the same numbers are redefined
+const (
+	snakeGradientPair int16 = 16
+	foodGlowPair      int16 = snakeGradientPair + gradientShades
+	bonusFoodGlowPair int16 = foodGlowPair + gradientShades
+	lastGradientPair  int16 = bonusFoodGlowPair + gradientShades - 1
+
+	gradientShades = 8
+	// snakeTailFading is the share of the background color mixed into the tail of the snake
+	snakeTailFading = 0.6
+	// foodGlowBrightening is the share of the white color mixed into the food at the peak of the glow
+	foodGlowBrightening = 0.6
+)
+
+// ThemeGlyphs are the characters the game objects are drawn with. Empty body shapes and walls fall back to
+// the ncurses line drawing characters, empty tail - to Body. The walls can only be drawn with the ASCII characters
+type ThemeGlyphs struct {
+	HeadUp    string `json:"headUp"`
+	HeadDown  string `json:"headDown"`
+	HeadLeft  string `json:"headLeft"`
+	HeadRight string `json:"headRight"`
+	Body      string `json:"body"`
+	// BodyHorizontal and BodyVertical are the straight body segments
+	BodyHorizontal string `json:"bodyHorizontal,omitempty"`
+	BodyVertical   string `json:"bodyVertical,omitempty"`
+	// the turns are named by the corner of the box they look like
+	TurnUpperLeft  string   `json:"turnUpperLeft,omitempty"`
+	TurnUpperRight string   `json:"turnUpperRight,omitempty"`
+	TurnLowerLeft  string   `json:"turnLowerLeft,omitempty"`
+	TurnLowerRight string   `json:"turnLowerRight,omitempty"`
+	Tail           string   `json:"tail,omitempty"`
+	Food           []string `json:"food"`
+	BonusFood      []string `json:"bonusFood"`
+	WallVertical   string   `json:"wallVertical,omitempty"`
+	WallHorizontal string   `json:"wallHorizontal,omitempty"`
+}
+
+// ThemePalette contains the color names of the game elements: black, red, green, yellow, blue, magenta, cyan or white
+type ThemePalette struct {
+	Title      string `json:"title"`
+	Snake      string `json:"snake"`
+	Text       string `json:"text"`
+	Food       string `json:"food"`
+	BonusFood  string `json:"bonusFood"`
+	Wall       string `json:"wall"`
+	Background string `json:"background"`
+}
+
+// Theme defines the look of the game. The themes are built in or loaded from the themes directory of the config
+type Theme struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Glyphs      ThemeGlyphs  `json:"glyphs"`
+	Palette     ThemePalette `json:"palette"`
+}
+
+var colorsByName = map[string]int16{
+	"black":   gc.C_BLACK,
+	"red":     gc.C_RED,
+	"green":   gc.C_GREEN,
+	"yellow":  gc.C_YELLOW,
+	"blue":    gc.C_BLUE,
+	"magenta": gc.C_MAGENTA,
+	"cyan":    gc.C_CYAN,
+	"white":   gc.C_WHITE}
+
+// rgb is the color with the components from 0 to 255
+type rgb struct {
+	r, g, b int
+}
+
+// colorsRGB are the colors of the palette as xterm shows them, the base of the gradients
+var colorsRGB = map[string]rgb{
+	"black":   rgb{0, 0, 0},
+	"red":     rgb{205, 0, 0},
+	"green":   rgb{0, 205, 0},
+	"yellow":  rgb{205, 205, 0},
+	"blue":    rgb{0, 0, 238},
+	"magenta": rgb{205, 0, 205},
+	"cyan":    rgb{0, 205, 205},
+	"white":   rgb{229, 229, 229}}
+
+var classicGlyphs = ThemeGlyphs{
+	HeadUp: `^`, HeadDown: `v`, HeadLeft: `<`, HeadRight: `>`,
+	Body:      `o`,
+	Food:      []string{`-`, `\`, `|`, `/`},
+	BonusFood: []string{`*`, `+`, `x`, `+`}}
+
+// highContrastGlyphs tell the objects apart without the colors, they replace the glyphs of any theme in the high contrast mode
+var highContrastGlyphs = ThemeGlyphs{
+	HeadUp: `^`, HeadDown: `v`, HeadLeft: `<`, HeadRight: `>`,
+	Body:           `O`,
+	Tail:           `.`,
+	Food:           []string{`@`},
+	BonusFood:      []string{`$`, `S`},
+	WallVertical:   `#`,
+	WallHorizontal: `#`}
+
+var builtinThemes = []*Theme{
+	&Theme{
+		Name:        classicThemeName,
+		Description: "The original ASCII look",
+		Glyphs:      classicGlyphs,
+		Palette: ThemePalette{
+			Title: "red", Snake: "green", Text: "yellow", Food: "red", BonusFood: "yellow", Wall: "white", Background: "black"}},
+
+	&Theme{
+		Name:        unicodeThemeName,
+		Description: "Box drawing and block characters for UTF-8 terminals",
+		Glyphs: ThemeGlyphs{
+			HeadUp: `▲`, HeadDown: `▼`, HeadLeft: `◀`, HeadRight: `▶`,
+			Body:           `■`,
+			BodyHorizontal: `═`,
+			BodyVertical:   `║`,
+			TurnUpperLeft:  `╔`,
+			TurnUpperRight: `╗`,
+			TurnLowerLeft:  `╚`,
+			TurnLowerRight: `╝`,
+			Tail:           `▪`,
+			Food:           []string{`◐`, `◓`, `◑`, `◒`},
+			BonusFood:      []string{`★`, `☆`}},
+		Palette: ThemePalette{
+			Title: "cyan", Snake: "green", Text: "white", Food: "red", BonusFood: "yellow", Wall: "blue", Background: "black"}},
+
+	&Theme{
+		Name:        highContrastThemeName,
+		Description: "Bright colors and distinct ASCII glyphs for every object",
+		Glyphs:      highContrastGlyphs,
+		Palette: ThemePalette{
+			Title: "white", Snake: "cyan", Text: "white", Food: "yellow", BonusFood: "magenta", Wall: "white", Background: "black"}},
+
+	&Theme{
+		Name:        deuteranopiaThemeName,
+		Description: "No red and green for the players not telling them apart",
+		Glyphs:      classicGlyphs,
+		Palette: ThemePalette{
+			Title: "yellow", Snake: "cyan", Text: "white", Food: "yellow", BonusFood: "white", Wall: "blue", Background: "black"}},
+
+	&Theme{
+		Name:        tritanopiaThemeName,
+		Description: "No blue and yellow for the players not telling them apart",
+		Glyphs:      classicGlyphs,
+		Palette: ThemePalette{
+			Title: "red", Snake: "cyan", Text: "white", Food: "red", BonusFood: "white", Wall: "white", Background: "black"}},
+
+	&Theme{
+		Name:        monochromeThemeName,
+		Description: "No colors, the objects are told apart by the glyphs",
+		Glyphs: ThemeGlyphs{
+			HeadUp: `A`, HeadDown: `V`, HeadLeft: `<`, HeadRight: `>`,
+			Body:           `o`,
+			BodyHorizontal: `-`,
+			BodyVertical:   `|`,
+			TurnUpperLeft:  `+`,
+			TurnUpperRight: `+`,
+			TurnLowerLeft:  `+`,
+			TurnLowerRight: `+`,
+			Tail:           `.`,
+			Food:           []string{`%`},
+			BonusFood:      []string{`$`},
+			// the line drawing characters of the snake body would look like the walls without the colors
+			WallVertical:   `#`,
+			WallHorizontal: `#`},
+		Palette: ThemePalette{
+			Title: "white", Snake: "white", Text: "white", Food: "white", BonusFood: "white", Wall: "white", Background: "black"}}}
+
+// currentTheme is the theme the game is drawn with
+var currentTheme = builtinThemes[0]
+
+// themeGlyphs returns the glyphs the game is drawn with: the ones of the current theme, or the high contrast ones
+func themeGlyphs() *ThemeGlyphs {
+	if gameConfig.HighContrast {
+		return &highContrastGlyphs
+	}
+	return &currentTheme.Glyphs
+}
+
+func themesDir() string {
+	return filepath.Join(configDir(), themesDirName)
+}
+
+// LoadThemes returns the built in themes followed by the ones loaded from the themes directory.
+// The loaded theme replaces the built in one of the same name. Invalid theme files are skipped
+func LoadThemes() []*Theme {
+	themes := append([]*Theme{}, builtinThemes...)
+	paths, globError := filepath.Glob(filepath.Join(themesDir(), "*"+themeFileExtension))
+	if globError != nil {
+		log.Println("Error listing themes: ", globError)
+		return themes
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		theme, loadError := LoadTheme(path)
+		if loadError != nil {
+			log.Println("Error loading theme "+path+": ", loadError)
+			continue
+		}
+		replaced := false
+		for idx, known := range themes {
+			if known.Name == theme.Name {
+				themes[idx], replaced = theme, true
+			}
+		}
+		if !replaced {
+			themes = append(themes, theme)
+		}
+	}
+	return themes
+}
+
+// LoadTheme reads the theme file. The theme is named after the file if it has no name
+func LoadTheme(path string) (*Theme, error) {
+	content, readError := ioutil.ReadFile(path)
+	if readError != nil {
+		return nil, readError
+	}
+
+	theme := &Theme{}
+	if parseError := json.Unmarshal(content, theme); parseError != nil {
+		return nil, parseError
+	}
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), themeFileExtension)
+	}
+	return theme, theme.Validate()
+}
+
+// Validate checks that the theme defines all of the required glyphs and known colors
+func (theme *Theme) Validate() error {
+	glyphs := theme.Glyphs
+	for _, glyph := range []string{glyphs.HeadUp, glyphs.HeadDown, glyphs.HeadLeft, glyphs.HeadRight, glyphs.Body} {
+		if glyph == "" {
+			return fmt.Errorf("theme %q misses the snake glyphs", theme.Name)
+		}
+	}
+	if len(glyphs.Food) == 0 || len(glyphs.BonusFood) == 0 {
+		return fmt.Errorf("theme %q misses the food glyphs", theme.Name)
+	}
+	for _, wall := range []string{glyphs.WallVertical, glyphs.WallHorizontal} {
+		if len(wall) > 1 {
+			return fmt.Errorf("theme %q wall glyph %q is not the single ASCII character", theme.Name, wall)
+		}
+	}
+
+	palette := theme.Palette
+	for _, color := range []string{palette.Title, palette.Snake, palette.Text, palette.Food, palette.BonusFood, palette.Wall, palette.Background} {
+		if _, known := colorsByName[color]; !known {
+			return fmt.Errorf("theme %q color %q is unknown", theme.Name, color)
+		}
+	}
+	return nil
+}
+
+// findTheme returns the theme of the name, the classic one if there is no such theme
+func findTheme(themes []*Theme, name string) *Theme {
+	for _, theme := range themes {
+		if theme.Name == name {
+			return theme
+		}
+	}
+	log.Printf("Theme %q not found, using %q", name, classicThemeName)
+	return builtinThemes[0]
+}
+
+// applyTheme initializes the color pairs and the food animations of the theme.
+// The objects already on the screen keep their glyphs until they are redrawn
+func applyTheme(theme *Theme) {
+	currentTheme = theme
+	if terminalColors != noColors {
+		initThemeColors(theme.Palette)
+	}
+
+	foodAnimation = NewAnimation(themeGlyphs().Food, 1)
+	bonusFoodAnimation = NewAnimation(themeGlyphs().BonusFood, 2)
+	if currentFood != nil {
+		currentFood.animation = foodAnimationOf(currentFood.kind)
+	}
+	log.Printf("Theme applied: %s", theme.Name)
+}
+
+func initThemeColors(palette ThemePalette) {
+	background := colorsByName[palette.Background]
+	for pair, color := range map[int16]string{
+		titleColorPair:     palette.Title,
+		snakeColorPair:     palette.Snake,
+		textColorPair:      palette.Text,
+		foodColorPair:      palette.Food,
+		bonusFoodColorPair: palette.BonusFood,
+		wallColorPair:      palette.Wall} {
+		gc.InitPair(pair, colorsByName[color], background)
+	}
+	if terminalColors < extendedColors {
+		return
+	}
+
+	white := colorsRGB["white"]
+	for shade := 0; shade < gradientShades; shade++ {
+		ratio := float64(shade) / float64(gradientShades-1)
+		initShade(snakeGradientPair+int16(shade), colorsRGB[palette.Snake].mix(colorsRGB[palette.Background], ratio*snakeTailFading), background)
+		initShade(foodGlowPair+int16(shade), colorsRGB[palette.Food].mix(white, ratio*foodGlowBrightening), background)
+		initShade(bonusFoodGlowPair+int16(shade), colorsRGB[palette.BonusFood].mix(white, ratio*foodGlowBrightening), background)
+	}
+}
+
+// initShade initializes the gradient pair with the color: redefined on the true color terminal,
+// the closest one of the xterm 256 colors otherwise
+func initShade(pair int16, color rgb, background int16) {
+	number := color.xtermColor()
+	if terminalColors == trueColors {
+		number = pair
+		if colorError := gc.InitColor(number, ncursesColorValue(color.r), ncursesColorValue(color.g), ncursesColorValue(color.b)); colorError != nil {
+			log.Println("Error initializing color: ", colorError)
+		}
+	}
+	gc.InitPair(pair, number, background)
+}
+
+// mix returns the color with the share of the other color mixed in
+func (color rgb) mix(other rgb, share float64) rgb {
+	blend := func(from int, to int) int {
+		return from + int(float64(to-from)*share)
+	}
+	return rgb{blend(color.r, other.r), blend(color.g, other.g), blend(color.b, other.b)}
+}
+
+// xtermColor returns the closest color of the xterm 6x6x6 color cube
+func (color rgb) xtermColor() int16 {
+	level := func(component int) int16 {
+		return int16((component*5 + 127) / 255)
+	}
+	return 16 + 36*level(color.r) + 6*level(color.g) + level(color.b)
+}
+
+// ncursesColorValue converts the color component to the range from 0 to 1000 used by ncurses
+func ncursesColorValue(component int) int16 {
+	return int16(component * 1000 / 255)
+}
+
+// snakeSegmentStyle returns the color pair and the attribute of the snake segment at the index from the head.
+// The body is shaded to the tail with the gradient if the terminal has enough colors, the tail third is dimmed otherwise
+func snakeSegmentStyle(idx int, length int) (int16, gc.Char) {
+	if terminalColors >= extendedColors {
+		return snakeGradientPair + int16(idx*gradientShades/maxInt(length, 1)), gc.A_BOLD
+	}
+	if idx > 0 && idx >= length*2/3 {
+		return snakeColorPair, gc.A_DIM
+	}
+	return snakeColorPair, gc.A_BOLD
+}
+
+// foodGlowStyle returns the color pair and the attribute of the food at the tick of the game.
+// The food brightens and fades back with the gradient, or blinks with the bold attribute without enough colors
+func foodGlowStyle(kind string, tick int) (int16, gc.Char) {
+	shade := tick % (2 * gradientShades)
+	if shade >= gradientShades {
+		shade = 2*gradientShades - 1 - shade
+	}
+
+	if terminalColors >= extendedColors {
+		if kind == bonusFoodKind {
+			return bonusFoodGlowPair + int16(shade), gc.A_BOLD
+		}
+		return foodGlowPair + int16(shade), gc.A_BOLD
+	}
+	pair := foodColorPair
+	if kind == bonusFoodKind {
+		pair = bonusFoodColorPair
+	}
+	if shade < gradientShades/2 {
+		return pair, gc.A_NORMAL
+	}
+	return pair, gc.A_BOLD
+}
+
+// foodAnimationOf returns the animation of the food kind
+func foodAnimationOf(kind string) Animation {
+	if kind == bonusFoodKind {
+		return bonusFoodAnimation
+	}
+	return foodAnimation
+}
+
+// headGlyph returns the glyph of the snake head moving in the direction
+func (glyphs *ThemeGlyphs) headGlyph(direction *point) string {
+	switch direction {
+	case up:
+		return glyphs.HeadUp
+	case down:
+		return glyphs.HeadDown
+	case left:
+		return glyphs.HeadLeft
+	}
+	return glyphs.HeadRight
+}
+
+// segmentGlyph returns the glyph of the body segment connected to its neighbours in the specified directions.
+// The glyph is empty if the theme has no glyph of the shape, the line drawing character should be drawn instead
+func (glyphs *ThemeGlyphs) segmentGlyph(toHead point, toTail point) (string, gc.Char) {
+	connects := func(first *point, second *point) bool {
+		return (toHead == *first && toTail == *second) || (toHead == *second && toTail == *first)
+	}
+	switch {
+	case connects(left, right):
+		return glyphs.BodyHorizontal, gc.ACS_HLINE
+	case connects(up, down):
+		return glyphs.BodyVertical, gc.ACS_VLINE
+	case connects(down, right):
+		return glyphs.TurnUpperLeft, gc.ACS_ULCORNER
+	case connects(down, left):
+		return glyphs.TurnUpperRight, gc.ACS_URCORNER
+	case connects(up, right):
+		return glyphs.TurnLowerLeft, gc.ACS_LLCORNER
+	case connects(up, left):
+		return glyphs.TurnLowerRight, gc.ACS_LRCORNER
+	}
+	return glyphs.Body, 0
+}
+
+// tailGlyph returns the glyph of the last body segment
+func (glyphs *ThemeGlyphs) tailGlyph() string {
+	if glyphs.Tail == "" {
+		return glyphs.Body
+	}
+	return glyphs.Tail
+}
+
+// drawGlyph prints the glyph, or the line drawing character if the glyph is empty
+func drawGlyph(w *gc.Window, y int, x int, glyph string, lineChar gc.Char) {
+	if glyph == "" {
+		w.MoveAddChar(y, x, lineChar)
+		return
+	}
+	w.MovePrint(y, x, glyph)
+}
+
+// wallChar converts the wall glyph to the ncurses character, 0 for the default line drawing one
+func wallChar(glyph string) gc.Char {
+	if glyph == "" {
+		return 0
+	}
+	return gc.Char(glyph[0])
+}
+
+// drawWalls draws the border of the game field
+func drawWalls(w *gc.Window) {
+	w.ColorOn(wallColorPair)
+	w.Box(wallChar(themeGlyphs().WallVertical), wallChar(themeGlyphs().WallHorizontal))
+	w.ColorOff(wallColorPair)
+}
+
+// themePreview shows the sample of the glyphs of the current theme in its colors
+type themePreview struct {
+	bounds widget.Rect
+}
+
+func (preview *themePreview) PreferredHeight() int         { return 3 }
+func (preview *themePreview) SetBounds(bounds widget.Rect) { preview.bounds = bounds }
+func (preview *themePreview) HandleKey(key gc.Key) bool    { return false }
+func (preview *themePreview) Focusable() bool              { return false }
+func (preview *themePreview) SetFocused(focused bool)      {}
+
+func (preview *themePreview) Draw(window *gc.Window) {
+	glyphs := themeGlyphs()
+	y, x := preview.bounds.Y, preview.bounds.X
+	if preview.bounds.Height < preview.PreferredHeight() {
+		return
+	}
+
+	window.MovePrint(y, x, "Snake:")
+	window.ColorOn(snakeColorPair)
+	window.AttrOn(gc.A_BOLD)
+	window.MovePrint(y, x+8, glyphs.tailGlyph())
+	straight, straightLine := glyphs.segmentGlyph(*right, *left)
+	for column := x + 9; column < x+11; column++ {
+		drawGlyph(window, y, column, straight, straightLine)
+	}
+	window.MovePrint(y, x+11, glyphs.headGlyph(right))
+	window.MovePrint(y, x+16, glyphs.HeadUp+" "+glyphs.HeadDown+" "+glyphs.HeadLeft+" "+glyphs.HeadRight)
+	turns := [][2]*point{{down, right}, {down, left}, {up, right}, {up, left}}
+	for idx, directions := range turns {
+		turn, turnLine := glyphs.segmentGlyph(*directions[0], *directions[1])
+		drawGlyph(window, y, x+26+idx*2, turn, turnLine)
+	}
+	window.AttrOff(gc.A_BOLD)
+	window.ColorOff(snakeColorPair)
+
+	window.MovePrint(y+1, x, "Food:")
+	window.ColorOn(foodColorPair)
+	window.MovePrint(y+1, x+8, strings.Join(glyphs.Food, " "))
+	window.ColorOff(foodColorPair)
+	window.ColorOn(bonusFoodColorPair)
+	window.MovePrint(y+1, x+18, strings.Join(glyphs.BonusFood, " "))
+	window.ColorOff(bonusFoodColorPair)
+
+	window.MovePrint(y+2, x, "Walls:")
+	window.ColorOn(wallColorPair)
+	vertical, horizontal := gc.ACS_VLINE, gc.ACS_HLINE
+	if glyphs.WallVertical != "" {
+		vertical = wallChar(glyphs.WallVertical)
+	}
+	if glyphs.WallHorizontal != "" {
+		horizontal = wallChar(glyphs.WallHorizontal)
+	}
+	window.MoveAddChar(y+2, x+8, vertical)
+	window.HLine(y+2, x+9, horizontal, 6)
+	window.MoveAddChar(y+2, x+15, vertical)
+	window.ColorOff(wallColorPair)
+}
+
+const (
+	themeWindowTitle  = "Theme"
+	themeWindowWidth  = 68
+	themeWindowHeight = 18
+	themeWindowHelp   = "Up/Down: preview  Space: high contrast  Enter: apply  Esc: cancel"
+	highContrastTitle = "High contrast glyphs for every theme"
+)
+
+// themeScene lets the player choose the theme and switch the high contrast mode. The selected theme is applied
+// to the whole screen immediately, the original theme and mode are restored if the choice is cancelled
+type themeScene struct {
+	parent               *gc.Window
+	dialog               *widget.Dialog
+	themes               []*Theme
+	original             *Theme
+	originalHighContrast bool
+	list                 *widget.List
+	description          *widget.Label
+	preview              *themePreview
+	highContrast         *widget.Checkbox
+	// redraw draws the screen under the dialog with the glyphs of the applied theme
+	redraw func()
+}
+
+func newThemeScene(parent *gc.Window, redraw func()) *themeScene {
+	return &themeScene{parent: parent, redraw: redraw}
+}
+
+func (scene *themeScene) Name() string { return "theme" }
+
+func (scene *themeScene) Enter() {
+	scene.themes = LoadThemes()
+	scene.original = currentTheme
+	scene.originalHighContrast = gameConfig.HighContrast
+
+	names := []string{}
+	for _, theme := range scene.themes {
+		names = append(names, theme.Name)
+	}
+	scene.list = widget.NewList(names...)
+	for idx, theme := range scene.themes {
+		if theme.Name == currentTheme.Name {
+			scene.list.Select(idx)
+		}
+	}
+	scene.description = widget.NewLabel("")
+	scene.preview = &themePreview{}
+	scene.highContrast = widget.NewCheckbox(highContrastTitle, gameConfig.HighContrast)
+	scene.highContrast.OnChange = func(checked bool) {
+		gameConfig.HighContrast = checked
+		applyTheme(currentTheme)
+		scene.redraw()
+	}
+
+	content := widget.NewColumn(scene.list, widget.NewSpacer(1), scene.description, widget.NewSpacer(1), scene.preview,
+		widget.NewSpacer(1), scene.highContrast)
+	dialog, dialogError := widget.NewDialog(scene.parent, themeWindowHeight, themeWindowWidth, themeWindowTitle, content)
+	if dialogError != nil {
+		log.Println("Error creating theme window: ", dialogError)
+		return
+	}
+	dialog.SetFooter(themeWindowHelp)
+	scene.dialog = dialog
+}
+
+func (scene *themeScene) Leave() {
+	if scene.dialog != nil {
+		scene.dialog.Close()
+	}
+}
+
+// previewSelected applies the selected theme
+func (scene *themeScene) previewSelected() {
+	theme := scene.themes[scene.list.Selected]
+	if theme != currentTheme {
+		applyTheme(theme)
+		scene.redraw()
+	}
+}
+
+func (scene *themeScene) HandleKey(key gc.Key) SceneTransition {
+	switch key {
+	case gc.KEY_RETURN, gc.KEY_ENTER:
+		gameConfig.Theme = currentTheme.Name
+		if saveError := gameConfig.Save(); saveError != nil {
+			log.Println("Error saving config: ", saveError)
+		}
+		return popScene()
+	case escapeKey, 'q':
+		if currentTheme != scene.original || gameConfig.HighContrast != scene.originalHighContrast {
+			gameConfig.HighContrast = scene.originalHighContrast
+			applyTheme(scene.original)
+			scene.redraw()
+		}
+		return popScene()
+	case ' ':
+		scene.highContrast.HandleKey(key)
+	default:
+		scene.list.HandleKey(key)
+		scene.previewSelected()
+	}
+	return stay()
+}
+
+func (scene *themeScene) Update() SceneTransition {
+	if scene.dialog == nil {
+		return popScene()
+	}
+	return stay()
+}
+
+func (scene *themeScene) Draw() {
+	if scene.dialog == nil {
+		return
+	}
+	scene.description.SetText(currentTheme.Description)
+	scene.dialog.Draw()
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"strings"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// titleIdleTicks is the amount of ticks without input after which the demo game starts
+const titleIdleTicks = 15 * speedFactor
+
+// titleLogoHoldFrames is the amount of ticks the fully revealed logo stays on the screen
+const titleLogoHoldFrames = 3 * speedFactor
+
+const titleLogoRevealStep = 2
+
+var titleLogo = []string{
+	"  ___  ___  _  _    _    _  __ ___ ",
+	" / __|/ __|| \\| |  /_\\  | |/ /| __|",
+	"| (_ |\\__ \\| .` | / _ \\ | ' < | _| ",
+	" \\___||___/|_|\\_|/_/ \\_\\|_|\\_\\|___|"}
+
+// newLogoAnimation creates the animation revealing the logo from the left to the right.
+// Every frame contains all of the logo lines separated by the new line
+func newLogoAnimation() Animation {
+	width := len(titleLogo[0])
+	frames := []string{}
+	for revealed := 0; revealed < width; revealed += titleLogoRevealStep {
+		lines := []string{}
+		for _, line := range titleLogo {
+			lines = append(lines, line[:revealed]+strings.Repeat(" ", width-revealed))
+		}
+		frames = append(frames, strings.Join(lines, "\n"))
+	}
+
+	frames = append(frames, strings.Join(titleLogo, "\n"))
+
+	// the full logo is held on the screen before it is revealed again
+	durations := uniformDurations(len(frames), 1)
+	durations[len(durations)-1] = titleLogoHoldFrames
+	return NewTimedAnimation(frames, durations, true)
+}
+
+// titleMenuItems returns the main menu items with the ones requiring the game in progress disabled
+func titleMenuItems() []*MenuItem {
+	return disabledMenuItems(menuOptionsKeySet, continueMenuItemTitle, saveGameMenuItemTitle)
+}
+
+// titleScene shows the logo and the main menu once the game is started. The demo game starts if the player is idle
+type titleScene struct {
+	window    *gc.Window
+	logo      Animation
+	menu      Menu
+	idleTicks int
+}
+
+func newTitleScene(window *gc.Window) *titleScene {
+	return &titleScene{window: window, logo: newLogoAnimation()}
+}
+
+func (scene *titleScene) Name() string { return "title" }
+
+func (scene *titleScene) Enter() {
+	if statsWindow != nil {
+		statsWindow.Erase()
+		statsWindow.Refresh()
+	}
+	scene.window.Erase()
+	drawWalls(scene.window)
+	scene.menu = NewMenu(scene.window, titleMenuItems())
+}
+
+func (scene *titleScene) Leave() {
+	scene.menu.Free()
+}
+
+// HandleKey passes the key to the menu and handles the event of the chosen menu item
+func (scene *titleScene) HandleKey(key gc.Key) SceneTransition {
+	scene.idleTicks = 0
+	if scene.menu.HandleKey(key) {
+		return stay()
+	}
+
+	transition := stay()
+	for {
+		select {
+		case event := <-events:
+			eventTransition := stay()
+			switch event.name {
+			case newGameEvent:
+				eventTransition = replaceScene(newPlayingScene(scene.window))
+			case loadGameEvent:
+				eventTransition = replaceScene(newLoadGameScene(scene.window,
+					func(saved *SavedGame) SceneTransition {
+						return replaceScene(newRestoredPlayingScene(scene.window, saved))
+					},
+					func() SceneTransition {
+						return replaceScene(newTitleScene(scene.window))
+					}))
+			case themeEvent:
+				// there is no game on the title screen to redraw with the new theme
+				eventTransition = pushScene(newThemeScene(scene.window, func() {
+					scene.window.Erase()
+					drawWalls(scene.window)
+				}))
+			case exitEvent:
+				// there is no game to save yet
+				eventTransition = quitGame()
+			default:
+				eventTransition = handleEvent(event, playerSnake, scene.window)
+			}
+			if transition.kind == stayTransition {
+				transition = eventTransition
+			}
+		default:
+			return transition
+		}
+	}
+}
+
+func (scene *titleScene) Update() SceneTransition {
+	scene.logo.MoveFrameIndex()
+	scene.menu.Update()
+	scene.idleTicks++
+	if scene.idleTicks >= titleIdleTicks {
+		return replaceScene(newDemoScene(scene.window))
+	}
+	return stay()
+}
+
+// UpdateBackground keeps the logo animated while the dialog opened from the menu is shown
+func (scene *titleScene) UpdateBackground() {
+	scene.logo.MoveFrameIndex()
+}
+
+func (scene *titleScene) Draw() {
+	_, cols := scene.window.MaxYX()
+	scene.window.ColorOn(snakeColorPair)
+	scene.window.AttrOn(gc.A_BOLD)
+	for idx, line := range strings.Split(scene.logo.CurrentFrame(), "\n") {
+		scene.window.MovePrint(1+idx, (cols-len(line))/2, line)
+	}
+	scene.window.AttrOff(gc.A_BOLD)
+	scene.window.ColorOff(snakeColorPair)
+	scene.window.Touch()
+	scene.window.NoutRefresh()
+	scene.menu.Refresh()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"log"
+
+	"github.com/VAlux/GSnake/widget"
+	gc "github.com/rthornton128/goncurses"
+)
+
+// toastDuration is the amount of game ticks the toast message stays on the screen
+const toastDuration = 3 * speedFactor
+
+// scorePopupDuration is the amount of game ticks the score breakdown stays on the screen
+const scorePopupDuration = 2 * speedFactor
+
+// toastOverlay shows the transient messages one by one in the small window over the game field
+type toastOverlay struct {
+	messages       []string
+	remainingTicks int
+	window         *gc.Window
+	duration       int
+	// bottom places the toast at the bottom of the game field instead of the top
+	bottom bool
+}
+
+var toasts = &toastOverlay{duration: toastDuration}
+
+var scorePopups = &toastOverlay{duration: scorePopupDuration, bottom: true}
+
+// Push queues the message to be shown
+func (toast *toastOverlay) Push(message string) {
+	toast.messages = append(toast.messages, message)
+}
+
+// Replace drops the queued messages and shows the specified one instead of the current one on the next update
+func (toast *toastOverlay) Replace(message string) {
+	toast.messages = []string{message}
+	toast.remainingTicks = 0
+}
+
+// Update advances the toast timer, switching to the next message when the current one expires.
+func (toast *toastOverlay) Update(parent *gc.Window) {
+	if toast.window != nil {
+		toast.remainingTicks--
+		if toast.remainingTicks > 0 {
+			return
+		}
+		toast.hide(parent)
+	}
+
+	if len(toast.messages) > 0 {
+		message := toast.messages[0]
+		toast.messages = toast.messages[1:]
+		toast.show(parent, message)
+	}
+}
+
+// Draw renders the current toast over the game field without updating the physical screen
+func (toast *toastOverlay) Draw() {
+	if toast.window != nil {
+		toast.window.Touch()
+		toast.window.NoutRefresh()
+	}
+}
+
+// Clear hides the current toast and drops the queued ones
+func (toast *toastOverlay) Clear(parent *gc.Window) {
+	toast.messages = nil
+	if toast.window != nil {
+		toast.hide(parent)
+	}
+}
+
+func (toast *toastOverlay) show(parent *gc.Window, message string) {
+	parentY, parentX := parent.YX()
+	lines, cols := parent.MaxYX()
+	width := minInt(len([]rune(message))+4, cols-2)
+	y := parentY + 1
+	if toast.bottom {
+		y = parentY + lines - 4
+	}
+
+	wnd, windowCreateError := createWindow(3, width, y, parentX+(cols-width)/2)
+	if windowCreateError != nil {
+		log.Println("Error creating toast window: ", windowCreateError)
+		return
+	}
+
+	wnd.Box(0, 0)
+	wnd.ColorOn(textColorPair)
+	wnd.AttrOn(gc.A_BOLD)
+	wnd.MovePrint(1, 2, widget.Clip(message, width-4))
+	wnd.AttrOff(gc.A_BOLD)
+	wnd.ColorOff(textColorPair)
+	wnd.Refresh()
+
+	toast.window = wnd
+	toast.remainingTicks = toast.duration
+}
+
+func (toast *toastOverlay) hide(parent *gc.Window) {
+	removeWindow(toast.window)
+	toast.window = nil
+	// repaint the game field hidden under the toast
+	parent.Touch()
+	parent.Refresh()
+}
@@ -0,0 +1,52 @@
+package widget
+
+import (
+	gc "github.com/rthornton128/goncurses"
+)
+
+// Button is the action pressed with Enter or Space while it is focused
+type Button struct {
+	base
+	Title   string
+	OnPress func()
+}
+
+// NewButton creates the button calling the function once it is pressed
+func NewButton(title string, onPress func()) *Button {
+	return &Button{Title: title, OnPress: onPress}
+}
+
+// PreferredHeight of the button is one line
+func (button *Button) PreferredHeight() int {
+	return 1
+}
+
+// PreferredWidth is the length of the title in the brackets
+func (button *Button) PreferredWidth() int {
+	return len([]rune(button.Title)) + 2
+}
+
+// Focusable returns true, the button is pressed by the keys
+func (button *Button) Focusable() bool {
+	return true
+}
+
+// HandleKey presses the button
+func (button *Button) HandleKey(key gc.Key) bool {
+	if !isActivationKey(key) {
+		return false
+	}
+	if button.OnPress != nil {
+		button.OnPress()
+	}
+	return true
+}
+
+// Draw prints the title in the brackets, in reverse video while the button is focused
+func (button *Button) Draw(window *gc.Window) {
+	if button.focused {
+		window.AttrOn(gc.A_REVERSE)
+	}
+	window.MovePrint(button.bounds.Y, button.bounds.X, Clip("["+button.Title+"]", button.bounds.Width))
+	window.AttrOff(gc.A_REVERSE)
+}
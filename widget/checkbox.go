@@ -0,0 +1,58 @@
+package widget
+
+import (
+	gc "github.com/rthornton128/goncurses"
+)
+
+// Checkbox is the option toggled with Enter or Space while it is focused
+type Checkbox struct {
+	base
+	Title    string
+	Checked  bool
+	OnChange func(checked bool)
+}
+
+// NewCheckbox creates the checkbox with the initial state
+func NewCheckbox(title string, checked bool) *Checkbox {
+	return &Checkbox{Title: title, Checked: checked}
+}
+
+// PreferredHeight of the checkbox is one line
+func (checkbox *Checkbox) PreferredHeight() int {
+	return 1
+}
+
+// PreferredWidth is the length of the mark and the title
+func (checkbox *Checkbox) PreferredWidth() int {
+	return len([]rune(checkbox.Title)) + 4
+}
+
+// Focusable returns true, the checkbox is toggled by the keys
+func (checkbox *Checkbox) Focusable() bool {
+	return true
+}
+
+// HandleKey toggles the checkbox
+func (checkbox *Checkbox) HandleKey(key gc.Key) bool {
+	if !isActivationKey(key) {
+		return false
+	}
+	checkbox.Checked = !checkbox.Checked
+	if checkbox.OnChange != nil {
+		checkbox.OnChange(checkbox.Checked)
+	}
+	return true
+}
+
+// Draw prints the mark and the title, in reverse video while the checkbox is focused
+func (checkbox *Checkbox) Draw(window *gc.Window) {
+	mark := "[ ] "
+	if checkbox.Checked {
+		mark = "[x] "
+	}
+	if checkbox.focused {
+		window.AttrOn(gc.A_REVERSE)
+	}
+	window.MovePrint(checkbox.bounds.Y, checkbox.bounds.X, Clip(mark+checkbox.Title, checkbox.bounds.Width))
+	window.AttrOff(gc.A_REVERSE)
+}
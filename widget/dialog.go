@@ -0,0 +1,107 @@
+package widget
+
+import (
+	gc "github.com/rthornton128/goncurses"
+)
+
+// Dialog is the frame with the widgets inside. The keys are passed to the focused widget,
+// Tab and Shift+Tab move the focus between the focusable widgets if the focused one does not consume them
+type Dialog struct {
+	*Frame
+	root       Widget
+	focusables []Widget
+	focusIndex int
+}
+
+// NewDialog creates the dialog window centered over the parent window with the root widget filling its content
+func NewDialog(parent *gc.Window, height int, width int, title string, root Widget) (*Dialog, error) {
+	frame, frameError := NewFrame(parent, height, width, title)
+	if frameError != nil {
+		return nil, frameError
+	}
+
+	dialog := &Dialog{Frame: frame, root: root}
+	dialog.Layout()
+	dialog.focusables = focusableWidgets(root)
+	dialog.setFocus(0)
+	return dialog, nil
+}
+
+// focusableWidgets collects the focusable widgets in the order they are laid out
+func focusableWidgets(widget Widget) []Widget {
+	widgets := []Widget{}
+	if parent, isContainer := widget.(container); isContainer {
+		for _, child := range parent.Children() {
+			widgets = append(widgets, focusableWidgets(child)...)
+		}
+	} else if widget.Focusable() {
+		widgets = append(widgets, widget)
+	}
+	return widgets
+}
+
+func (dialog *Dialog) setFocus(index int) {
+	if len(dialog.focusables) == 0 {
+		return
+	}
+	dialog.focusables[dialog.focusIndex].SetFocused(false)
+	dialog.focusIndex = (index + len(dialog.focusables)) % len(dialog.focusables)
+	dialog.focusables[dialog.focusIndex].SetFocused(true)
+}
+
+// Focus moves the focus to the widget, if it is focusable
+func (dialog *Dialog) Focus(widget Widget) {
+	for idx, focusable := range dialog.focusables {
+		if focusable == widget {
+			dialog.setFocus(idx)
+		}
+	}
+}
+
+// Focused returns the widget receiving the input, nil if there are no focusable widgets
+func (dialog *Dialog) Focused() Widget {
+	if len(dialog.focusables) == 0 {
+		return nil
+	}
+	return dialog.focusables[dialog.focusIndex]
+}
+
+// HandleKey passes the key to the focused widget or moves the focus. Returns true if the key is consumed
+func (dialog *Dialog) HandleKey(key gc.Key) bool {
+	if focused := dialog.Focused(); focused != nil && focused.HandleKey(key) {
+		return true
+	}
+
+	switch key {
+	case gc.KEY_TAB:
+		dialog.setFocus(dialog.focusIndex + 1)
+	case gc.KEY_BTAB:
+		dialog.setFocus(dialog.focusIndex - 1)
+	default:
+		return false
+	}
+	return true
+}
+
+// SetFooter replaces the footer of the frame, resizing the content
+func (dialog *Dialog) SetFooter(footer string) {
+	dialog.Footer = footer
+	dialog.Layout()
+}
+
+// Layout places the widgets into the content of the frame
+func (dialog *Dialog) Layout() {
+	dialog.root.SetBounds(dialog.Content())
+}
+
+// Draw lays out and renders the widgets without updating the physical screen
+func (dialog *Dialog) Draw() {
+	dialog.Clear()
+	dialog.Layout()
+	dialog.root.Draw(dialog.window)
+
+	if owner, ownsCursor := dialog.Focused().(cursorOwner); ownsCursor {
+		dialog.window.Move(owner.CursorPosition())
+	}
+	dialog.window.NoutRefresh()
+}
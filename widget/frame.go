@@ -0,0 +1,86 @@
+package widget
+
+import (
+	"errors"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// Frame is the boxed window with the title separated from the content.
+// The optional footer, like the key help, is separated from the content at the bottom
+type Frame struct {
+	Title  string
+	Footer string
+
+	window *gc.Window
+	height int
+	width  int
+}
+
+// NewFrame creates the frame window centered over the parent window. The size is limited by the parent size
+func NewFrame(parent *gc.Window, height int, width int, title string) (*Frame, error) {
+	parentY, parentX := parent.YX()
+	lines, cols := parent.MaxYX()
+	height, width = minInt(height, lines), minInt(width, cols)
+
+	window, windowCreateError := gc.NewWindow(height, width, parentY+(lines-height)/2, parentX+(cols-width)/2)
+	if windowCreateError != nil {
+		return nil, errors.New("Error during creating the window: " + windowCreateError.Error())
+	}
+	window.Keypad(true)
+	return &Frame{Title: title, window: window, height: height, width: width}, nil
+}
+
+// Window returns the ncurses window of the frame
+func (frame *Frame) Window() *gc.Window {
+	return frame.window
+}
+
+// Size returns the height and the width of the frame
+func (frame *Frame) Size() (int, int) {
+	return frame.height, frame.width
+}
+
+// Content returns the rectangle inside of the frame available for the widgets
+func (frame *Frame) Content() Rect {
+	height := frame.height - 4
+	if frame.Footer != "" {
+		height -= 2
+	}
+	return Rect{Y: 3, X: 2, Height: maxInt(0, height), Width: maxInt(0, frame.width-4)}
+}
+
+// Clear erases the frame window and draws the box, the title and the footer
+func (frame *Frame) Clear() {
+	frame.window.Erase()
+	DrawFrame(frame.window, frame.Title)
+	if frame.Footer != "" {
+		DrawSeparator(frame.window, frame.height-3)
+		frame.window.MovePrint(frame.height-2, 2, Clip(frame.Footer, frame.width-4))
+	}
+}
+
+// Close removes the frame window from the screen
+func (frame *Frame) Close() {
+	frame.window.Erase()
+	frame.window.Refresh()
+	frame.window.Delete()
+}
+
+// DrawFrame draws the box with the title separated from the content of the window
+func DrawFrame(window *gc.Window, title string) {
+	_, width := window.MaxYX()
+	window.Box(0, 0)
+	window.ColorOn(TitleColor)
+	window.MovePrint(1, (width/2)-(len(title)/2), title)
+	window.ColorOff(TitleColor)
+	DrawSeparator(window, 2)
+}
+
+// DrawSeparator draws the horizontal line joined with the box of the window
+func DrawSeparator(window *gc.Window, y int) {
+	_, width := window.MaxYX()
+	window.MoveAddChar(y, 0, gc.ACS_LTEE)
+	window.HLine(y, 1, gc.ACS_HLINE, width-2)
+	window.MoveAddChar(y, width-1, gc.ACS_RTEE)
+}
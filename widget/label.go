@@ -0,0 +1,65 @@
+package widget
+
+import (
+	gc "github.com/rthornton128/goncurses"
+)
+
+// Label is the static text, one line per element of Lines
+type Label struct {
+	base
+	Lines    []string
+	Color    int16
+	Bold     bool
+	Centered bool
+	// Fill makes the label take all of the free space of the column instead of the height of its lines
+	Fill bool
+}
+
+// NewLabel creates the label of the text lines drawn with the text color
+func NewLabel(lines ...string) *Label {
+	return &Label{Lines: lines, Color: TextColor}
+}
+
+// SetText replaces the lines of the label
+func (label *Label) SetText(lines ...string) {
+	label.Lines = lines
+}
+
+// PreferredHeight returns the amount of the lines
+func (label *Label) PreferredHeight() int {
+	if label.Fill {
+		return 0
+	}
+	return len(label.Lines)
+}
+
+// PreferredWidth returns the length of the longest line
+func (label *Label) PreferredWidth() int {
+	width := 0
+	for _, line := range label.Lines {
+		width = maxInt(width, len([]rune(line)))
+	}
+	return width
+}
+
+// Draw prints the lines fitting into the bounds
+func (label *Label) Draw(window *gc.Window) {
+	bounds := label.bounds
+	window.ColorOn(label.Color)
+	if label.Bold {
+		window.AttrOn(gc.A_BOLD)
+	}
+	for idx, line := range label.Lines {
+		if idx >= bounds.Height {
+			break
+		}
+		line = Clip(line, bounds.Width)
+		x := bounds.X
+		if label.Centered {
+			x += (bounds.Width - len([]rune(line))) / 2
+		}
+		window.MovePrint(bounds.Y+idx, x, line)
+	}
+	window.AttrOff(gc.A_BOLD)
+	window.ColorOff(label.Color)
+}
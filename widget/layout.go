@@ -0,0 +1,148 @@
+package widget
+
+import (
+	gc "github.com/rthornton128/goncurses"
+)
+
+// Column lays out the widgets from the top to the bottom. The widgets without the preferred height
+// share the space left by the other ones
+type Column struct {
+	base
+	children []Widget
+}
+
+// NewColumn creates the column of the widgets
+func NewColumn(children ...Widget) *Column {
+	return &Column{children: children}
+}
+
+// Children returns the widgets of the column
+func (column *Column) Children() []Widget {
+	return column.children
+}
+
+// PreferredHeight sums the heights of the widgets, 0 if any of them fills the free space
+func (column *Column) PreferredHeight() int {
+	height := 0
+	for _, child := range column.children {
+		if child.PreferredHeight() == 0 {
+			return 0
+		}
+		height += child.PreferredHeight()
+	}
+	return height
+}
+
+// SetBounds distributes the rectangle between the widgets
+func (column *Column) SetBounds(bounds Rect) {
+	column.bounds = bounds
+	fixedHeight, fillers := 0, 0
+	for _, child := range column.children {
+		if child.PreferredHeight() == 0 {
+			fillers++
+		}
+		fixedHeight += child.PreferredHeight()
+	}
+
+	free := maxInt(0, bounds.Height-fixedHeight)
+	y := bounds.Y
+	for _, child := range column.children {
+		height := child.PreferredHeight()
+		if height == 0 {
+			height = free / fillers
+			free -= height
+			fillers--
+		}
+		height = maxInt(0, minInt(height, bounds.Y+bounds.Height-y))
+		child.SetBounds(Rect{Y: y, X: bounds.X, Height: height, Width: bounds.Width})
+		y += height
+	}
+}
+
+// Draw renders the widgets
+func (column *Column) Draw(window *gc.Window) {
+	for _, child := range column.children {
+		if boundsOf(child).Height > 0 {
+			child.Draw(window)
+		}
+	}
+}
+
+// boundsOf returns the bounds of the widget set by the layout
+func boundsOf(widget Widget) Rect {
+	if bounded, hasBounds := widget.(interface{ Bounds() Rect }); hasBounds {
+		return bounded.Bounds()
+	}
+	return Rect{}
+}
+
+// Row lays out the widgets from the left to the right in the single line, centered horizontally
+type Row struct {
+	base
+	children []Widget
+	// Gap is the amount of columns between the widgets
+	Gap int
+}
+
+// NewRow creates the row of the widgets separated by 2 columns
+func NewRow(children ...Widget) *Row {
+	return &Row{children: children, Gap: 2}
+}
+
+// Children returns the widgets of the row
+func (row *Row) Children() []Widget {
+	return row.children
+}
+
+// PreferredHeight of the row is one line
+func (row *Row) PreferredHeight() int {
+	return 1
+}
+
+// SetBounds places the widgets one after another in the middle of the rectangle
+func (row *Row) SetBounds(bounds Rect) {
+	row.bounds = bounds
+	widths := []int{}
+	total := (len(row.children) - 1) * row.Gap
+	for _, child := range row.children {
+		width := bounds.Width
+		if hinter, hasWidth := child.(widthHinter); hasWidth {
+			width = hinter.PreferredWidth()
+		}
+		widths = append(widths, width)
+		total += width
+	}
+
+	x := bounds.X + maxInt(0, (bounds.Width-total)/2)
+	for idx, child := range row.children {
+		width := maxInt(0, minInt(widths[idx], bounds.X+bounds.Width-x))
+		child.SetBounds(Rect{Y: bounds.Y, X: x, Height: 1, Width: width})
+		x += widths[idx] + row.Gap
+	}
+}
+
+// Draw renders the widgets
+func (row *Row) Draw(window *gc.Window) {
+	for _, child := range row.children {
+		child.Draw(window)
+	}
+}
+
+// Spacer is the empty space of the layout
+type Spacer struct {
+	base
+	height int
+}
+
+// NewSpacer creates the empty space of the specified height, 0 to fill all of the free space
+func NewSpacer(height int) *Spacer {
+	return &Spacer{height: height}
+}
+
+// PreferredHeight returns the height of the space
+func (spacer *Spacer) PreferredHeight() int {
+	return spacer.height
+}
+
+// Draw does nothing
+func (spacer *Spacer) Draw(window *gc.Window) {}
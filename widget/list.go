@@ -0,0 +1,173 @@
+package widget
+
+import (
+	gc "github.com/rthornton128/goncurses"
+)
+
+// List is the scrollable list of the text items with the selected one highlighted
+type List struct {
+	base
+	Items    []string
+	Selected int
+	// Marker is printed in front of the selected item, the other items are indented by its length.
+	// The selected item is shown in reverse video if there is no marker
+	Marker string
+	// Wrap moves the selection from the last item to the first one and back
+	Wrap bool
+	// Disabled marks the items which are dimmed and skipped by the selection, by their indexes
+	Disabled []bool
+	// Hotkeys are the indexes of the underlined characters of the items, -1 if the item has no hotkey
+	Hotkeys []int
+	offset  int
+}
+
+// NewList creates the list of the items with the first one selected
+func NewList(items ...string) *List {
+	return &List{Items: items}
+}
+
+// SetItems replaces the items, keeping the selection within them
+func (list *List) SetItems(items []string) {
+	list.Items = items
+	list.Select(list.Selected)
+}
+
+// Select moves the selection to the item, limited by the amount of the items
+func (list *List) Select(index int) {
+	list.Selected = maxInt(0, minInt(index, len(list.Items)-1))
+}
+
+// Enabled checks if the item can be selected
+func (list *List) Enabled(index int) bool {
+	return index >= 0 && index < len(list.Items) && (index >= len(list.Disabled) || !list.Disabled[index])
+}
+
+// SelectFirstEnabled moves the selection to the first enabled item starting from the index
+func (list *List) SelectFirstEnabled(index int) {
+	list.Select(index)
+	if !list.Enabled(list.Selected) {
+		list.move(1, true)
+	}
+}
+
+// move shifts the selection by the step of enabled items, 0 to move to the next enabled item if the selected one is disabled.
+// The selection stops at the last enabled item found if there are not enough of them
+func (list *List) move(step int, wrap bool) {
+	if step == 0 {
+		if list.Enabled(list.Selected) {
+			return
+		}
+		step = 1
+	}
+	direction := 1
+	if step < 0 {
+		direction = -1
+	}
+
+	amount := len(list.Items)
+	index, target := list.Selected, list.Selected
+	for moved := 0; moved < amount && step != 0; moved++ {
+		index += direction
+		if index < 0 || index >= amount {
+			if !wrap {
+				break
+			}
+			index = (index + amount) % amount
+		}
+		if list.Enabled(index) {
+			target = index
+			step -= direction
+		}
+	}
+	list.Selected = target
+}
+
+// ItemAt returns the index of the item shown in the row of the window, -1 if there is no item there
+func (list *List) ItemAt(y int) int {
+	row := y - list.bounds.Y
+	if row < 0 || row >= list.bounds.Height || list.offset+row >= len(list.Items) {
+		return -1
+	}
+	return list.offset + row
+}
+
+// PreferredHeight is 0, so the list fills the free space
+func (list *List) PreferredHeight() int {
+	return 0
+}
+
+// Focusable returns true, the list receives the keys moving the selection
+func (list *List) Focusable() bool {
+	return true
+}
+
+// HandleKey moves the selection
+func (list *List) HandleKey(key gc.Key) bool {
+	amount := len(list.Items)
+	if amount == 0 {
+		return false
+	}
+
+	switch key {
+	case gc.KEY_UP:
+		list.move(-1, list.Wrap)
+	case gc.KEY_DOWN:
+		list.move(1, list.Wrap)
+	case gc.KEY_PAGEUP:
+		list.move(-maxInt(1, list.bounds.Height), false)
+	case gc.KEY_PAGEDOWN:
+		list.move(maxInt(1, list.bounds.Height), false)
+	case gc.KEY_HOME:
+		list.Selected = 0
+		list.move(0, false)
+	case gc.KEY_END:
+		list.Selected = amount - 1
+		list.move(0, false)
+		if !list.Enabled(list.Selected) {
+			list.move(-1, false)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// Draw prints the visible items, scrolling the list to keep the selected item in view
+func (list *List) Draw(window *gc.Window) {
+	bounds := list.bounds
+	if bounds.Height <= 0 {
+		return
+	}
+	if list.Selected < list.offset {
+		list.offset = list.Selected
+	} else if list.Selected >= list.offset+bounds.Height {
+		list.offset = list.Selected - bounds.Height + 1
+	}
+
+	emptyMarker := Pad("", len([]rune(list.Marker)))
+	for row := 0; row < bounds.Height && list.offset+row < len(list.Items); row++ {
+		idx := list.offset + row
+		selected := idx == list.Selected
+		prefix := emptyMarker
+		if selected && list.Marker != "" {
+			prefix = list.Marker
+		}
+		if selected && list.Marker == "" {
+			window.AttrOn(gc.A_REVERSE)
+		}
+		if !list.Enabled(idx) {
+			window.AttrOn(gc.A_DIM)
+		}
+		window.MovePrint(bounds.Y+row, bounds.X, Clip(prefix+list.Items[idx], bounds.Width))
+
+		if idx < len(list.Hotkeys) && list.Hotkeys[idx] >= 0 {
+			position := len([]rune(prefix)) + list.Hotkeys[idx]
+			if runes := []rune(list.Items[idx]); list.Hotkeys[idx] < len(runes) && position < bounds.Width {
+				window.AttrOn(gc.A_UNDERLINE)
+				window.MovePrint(bounds.Y+row, bounds.X+position, string(runes[list.Hotkeys[idx]]))
+				window.AttrOff(gc.A_UNDERLINE)
+			}
+		}
+		window.AttrOff(gc.A_REVERSE | gc.A_DIM)
+	}
+}
@@ -0,0 +1,147 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// TableColumn describes the column of the table. The column of zero width takes the rest of the line
+type TableColumn struct {
+	Title      string
+	Width      int
+	AlignRight bool
+}
+
+// Table is the scrollable table with the bold header. Without the selection the keys scroll the rows,
+// otherwise they move the selection
+type Table struct {
+	base
+	Columns []TableColumn
+	Rows    [][]string
+	// Selected is the index of the selected row, -1 if the rows are not selectable
+	Selected int
+	// Highlighted is the index of the row shown in reverse video, -1 if there is none
+	Highlighted int
+	offset      int
+}
+
+// NewTable creates the table without the selection
+func NewTable(columns ...TableColumn) *Table {
+	return &Table{Columns: columns, Selected: -1, Highlighted: -1}
+}
+
+// SetRows replaces the rows and scrolls the table to the top
+func (table *Table) SetRows(rows [][]string) {
+	table.Rows = rows
+	table.offset = 0
+	if table.Selected >= 0 {
+		table.Selected = maxInt(0, minInt(table.Selected, len(rows)-1))
+	}
+}
+
+// PreferredHeight is 0, so the table fills the free space
+func (table *Table) PreferredHeight() int {
+	return 0
+}
+
+// Focusable returns true, the table receives the scrolling keys
+func (table *Table) Focusable() bool {
+	return true
+}
+
+// PageSize returns the amount of the rows fitting under the header
+func (table *Table) PageSize() int {
+	return maxInt(1, table.bounds.Height-1)
+}
+
+// Scroll moves the visible rows by the amount of rows, negative to scroll up
+func (table *Table) Scroll(rows int) {
+	maxOffset := maxInt(0, len(table.Rows)-table.PageSize())
+	table.offset = maxInt(0, minInt(maxOffset, table.offset+rows))
+}
+
+// ScrollTo shows the row in the middle of the table
+func (table *Table) ScrollTo(row int) {
+	table.Scroll(row - table.offset - table.PageSize()/2)
+}
+
+// HandleKey scrolls the rows or moves the selection
+func (table *Table) HandleKey(key gc.Key) bool {
+	step := 0
+	switch key {
+	case gc.KEY_UP:
+		step = -1
+	case gc.KEY_DOWN:
+		step = 1
+	case gc.KEY_PAGEUP:
+		step = -table.PageSize()
+	case gc.KEY_PAGEDOWN:
+		step = table.PageSize()
+	case gc.KEY_HOME:
+		step = -len(table.Rows)
+	case gc.KEY_END:
+		step = len(table.Rows)
+	default:
+		return false
+	}
+
+	if table.Selected < 0 {
+		table.Scroll(step)
+		return true
+	}
+	table.Selected = maxInt(0, minInt(len(table.Rows)-1, table.Selected+step))
+	if table.Selected < table.offset {
+		table.offset = table.Selected
+	} else if table.Selected >= table.offset+table.PageSize() {
+		table.offset = table.Selected - table.PageSize() + 1
+	}
+	return true
+}
+
+// FormatRow aligns the cells by the column widths
+func (table *Table) FormatRow(cells []string) string {
+	parts := []string{}
+	for idx, column := range table.Columns {
+		cell := ""
+		if idx < len(cells) {
+			cell = cells[idx]
+		}
+		switch {
+		case column.Width == 0:
+			parts = append(parts, cell)
+		case column.AlignRight:
+			parts = append(parts, fmt.Sprintf("%*s", column.Width, Clip(cell, column.Width)))
+		default:
+			parts = append(parts, Pad(cell, column.Width))
+		}
+	}
+	return strings.TrimRight(strings.Join(parts, " "), " ")
+}
+
+// Draw prints the header and the visible rows
+func (table *Table) Draw(window *gc.Window) {
+	bounds := table.bounds
+	if bounds.Height <= 0 {
+		return
+	}
+	header := []string{}
+	for _, column := range table.Columns {
+		header = append(header, column.Title)
+	}
+	window.AttrOn(gc.A_BOLD)
+	window.MovePrint(bounds.Y, bounds.X, Clip(table.FormatRow(header), bounds.Width))
+	window.AttrOff(gc.A_BOLD)
+
+	window.ColorOn(TextColor)
+	for row := 0; row < table.PageSize() && table.offset+row < len(table.Rows); row++ {
+		idx := table.offset + row
+		if idx == table.Highlighted || idx == table.Selected {
+			window.AttrOn(gc.A_REVERSE)
+		}
+		window.MovePrint(bounds.Y+1+row, bounds.X, Clip(table.FormatRow(table.Rows[idx]), bounds.Width))
+		window.AttrOff(gc.A_REVERSE)
+	}
+	window.ColorOff(TextColor)
+}
@@ -0,0 +1,128 @@
+package widget
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// TextInput is the single line text field with the prompt in front of it
+type TextInput struct {
+	base
+	Prompt    string
+	MaxLength int
+	text      []rune
+	cursor    int
+	// pending collects the bytes of the UTF-8 encoded character, which are read as separate keys
+	pending []byte
+}
+
+// NewTextInput creates the text field filled in with the text, the cursor is placed at its end
+func NewTextInput(prompt string, text string, maxLength int) *TextInput {
+	input := &TextInput{Prompt: prompt, MaxLength: maxLength}
+	input.SetText(text)
+	return input
+}
+
+// Text returns the entered text
+func (input *TextInput) Text() string {
+	return string(input.text)
+}
+
+// SetText replaces the entered text, cutting it to the maximal length
+func (input *TextInput) SetText(text string) {
+	input.text = []rune(text)
+	if input.MaxLength > 0 && len(input.text) > input.MaxLength {
+		input.text = input.text[:input.MaxLength]
+	}
+	input.cursor = len(input.text)
+}
+
+// PreferredHeight of the text field is one line
+func (input *TextInput) PreferredHeight() int {
+	return 1
+}
+
+// Focusable returns true, the text field receives the typed characters
+func (input *TextInput) Focusable() bool {
+	return true
+}
+
+// HandleKey edits the text. The printable characters are inserted at the cursor position
+func (input *TextInput) HandleKey(key gc.Key) bool {
+	if key >= utf8.RuneSelf && key <= 0xff {
+		return input.handleByte(byte(key))
+	}
+	input.pending = nil
+
+	switch {
+	case key == gc.KEY_BACKSPACE || key == BackspaceKey || key == 8:
+		if input.cursor > 0 {
+			input.text = append(input.text[:input.cursor-1], input.text[input.cursor:]...)
+			input.cursor--
+		}
+	case key == gc.KEY_DC:
+		if input.cursor < len(input.text) {
+			input.text = append(input.text[:input.cursor], input.text[input.cursor+1:]...)
+		}
+	case key == gc.KEY_LEFT:
+		input.cursor = maxInt(0, input.cursor-1)
+	case key == gc.KEY_RIGHT:
+		input.cursor = minInt(len(input.text), input.cursor+1)
+	case key == gc.KEY_HOME:
+		input.cursor = 0
+	case key == gc.KEY_END:
+		input.cursor = len(input.text)
+	case key >= ' ' && key < utf8.RuneSelf:
+		input.insert(rune(key))
+	default:
+		return false
+	}
+	return true
+}
+
+// handleByte collects the bytes of the multibyte character and inserts it once it is complete
+func (input *TextInput) handleByte(value byte) bool {
+	input.pending = append(input.pending, value)
+	if !utf8.FullRune(input.pending) {
+		return true
+	}
+
+	character, _ := utf8.DecodeRune(input.pending)
+	input.pending = nil
+	if character != utf8.RuneError && unicode.IsPrint(character) {
+		input.insert(character)
+	}
+	return true
+}
+
+func (input *TextInput) insert(character rune) {
+	if input.MaxLength > 0 && len(input.text) >= input.MaxLength {
+		return
+	}
+	input.text = append(input.text[:input.cursor], append([]rune{character}, input.text[input.cursor:]...)...)
+	input.cursor++
+}
+
+// CursorPosition returns the position of the cursor in the window
+func (input *TextInput) CursorPosition() (int, int) {
+	x := input.bounds.X + len([]rune(input.Prompt)) + input.cursor
+	return input.bounds.Y, minInt(x, input.bounds.X+input.bounds.Width-1)
+}
+
+// Draw prints the prompt and the text. The empty part of the field is underlined while it is focused
+func (input *TextInput) Draw(window *gc.Window) {
+	bounds := input.bounds
+	window.MovePrint(bounds.Y, bounds.X, Clip(input.Prompt, bounds.Width))
+
+	fieldWidth := bounds.Width - len([]rune(input.Prompt))
+	if input.MaxLength > 0 {
+		fieldWidth = minInt(fieldWidth, input.MaxLength)
+	}
+	if input.focused {
+		window.AttrOn(gc.A_UNDERLINE)
+	}
+	window.Print(Pad(string(input.text), fieldWidth))
+	window.AttrOff(gc.A_UNDERLINE)
+}
@@ -0,0 +1,148 @@
+package widget
+
+import (
+	"strings"
+
+	gc "github.com/rthornton128/goncurses"
+)
+
+// TextView is the word wrapped text scrolled with the arrow keys once it does not fit into its bounds.
+// It does not take the focus, the scrolling keys are passed to it by its owner
+type TextView struct {
+	base
+	Lines  []string
+	Color  int16
+	offset int
+}
+
+// NewTextView creates the view of the text lines drawn with the text color
+func NewTextView(lines ...string) *TextView {
+	return &TextView{Lines: lines, Color: TextColor}
+}
+
+// PreferredHeight is 0, so the view fills the free space
+func (view *TextView) PreferredHeight() int {
+	return 0
+}
+
+// wrapped returns the lines wrapped to the width of the view, leaving the column for the scroll marks if needed
+func (view *TextView) wrapped() []string {
+	lines := WrapLines(view.Lines, view.bounds.Width)
+	if len(lines) > view.bounds.Height {
+		lines = WrapLines(view.Lines, view.bounds.Width-1)
+	}
+	return lines
+}
+
+// Scrollable checks if the text does not fit into the view
+func (view *TextView) Scrollable() bool {
+	return len(view.wrapped()) > view.bounds.Height
+}
+
+// Scroll moves the text by the amount of lines, negative to scroll up
+func (view *TextView) Scroll(lines int) {
+	maxOffset := maxInt(0, len(view.wrapped())-view.bounds.Height)
+	view.offset = maxInt(0, minInt(maxOffset, view.offset+lines))
+}
+
+// HandleKey scrolls the text. The keys are not consumed if the whole text is visible
+func (view *TextView) HandleKey(key gc.Key) bool {
+	if !view.Scrollable() {
+		return false
+	}
+
+	page := maxInt(1, view.bounds.Height-1)
+	switch key {
+	case gc.KEY_UP:
+		view.Scroll(-1)
+	case gc.KEY_DOWN:
+		view.Scroll(1)
+	case gc.KEY_PAGEUP:
+		view.Scroll(-page)
+	case gc.KEY_PAGEDOWN:
+		view.Scroll(page)
+	case gc.KEY_HOME:
+		view.Scroll(-len(view.Lines) * view.bounds.Width)
+	case gc.KEY_END:
+		view.Scroll(len(view.Lines) * view.bounds.Width)
+	default:
+		return false
+	}
+	return true
+}
+
+// Draw prints the visible part of the text with the marks of the hidden lines in the right column
+func (view *TextView) Draw(window *gc.Window) {
+	bounds := view.bounds
+	lines := view.wrapped()
+	view.Scroll(0)
+
+	window.ColorOn(view.Color)
+	for row := 0; row < bounds.Height && view.offset+row < len(lines); row++ {
+		window.MovePrint(bounds.Y+row, bounds.X, Clip(lines[view.offset+row], bounds.Width))
+	}
+	window.ColorOff(view.Color)
+
+	if len(lines) <= bounds.Height || bounds.Height <= 0 {
+		return
+	}
+	if view.offset > 0 {
+		window.MoveAddChar(bounds.Y, bounds.X+bounds.Width-1, gc.ACS_UARROW)
+	}
+	if view.offset+bounds.Height < len(lines) {
+		window.MoveAddChar(bounds.Y+bounds.Height-1, bounds.X+bounds.Width-1, gc.ACS_DARROW)
+	}
+}
+
+// WrapLines wraps each of the lines by the words to fit into the width, keeping the empty lines
+func WrapLines(lines []string, width int) []string {
+	wrapped := []string{}
+	for _, line := range lines {
+		wrapped = append(wrapped, Wrap(line, width)...)
+	}
+	return wrapped
+}
+
+// Wrap splits the text by the words into the lines not longer than the width.
+// The indentation of the text is kept in its first line, the words longer than the width are cut
+func Wrap(text string, width int) []string {
+	if width <= 0 {
+		return []string{}
+	}
+	trimmed := strings.TrimLeft(text, " ")
+	indent := text[:len(text)-len(trimmed)]
+	if len([]rune(indent)) >= width {
+		indent = ""
+	}
+
+	lines := []string{}
+	current := indent
+	for _, word := range strings.Fields(trimmed) {
+		for len([]rune(word)) > width {
+			if current != "" && current != indent {
+				lines = append(lines, current)
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+			current = ""
+		}
+
+		switch {
+		case word == "":
+			continue
+		case current == "" || current == indent:
+			if len([]rune(current+word)) > width {
+				current = word
+			} else {
+				current += word
+			}
+		case len([]rune(current))+1+len([]rune(word)) > width:
+			lines = append(lines, current)
+			current = word
+		default:
+			current += " " + word
+		}
+	}
+	return append(lines, current)
+}
@@ -0,0 +1,129 @@
+// Package widget contains the small toolkit of the ncurses widgets the game screens are built of:
+// the framed dialog windows with the labels, lists, text inputs, buttons, checkboxes and tables inside,
+// arranged by the column and row layouts, with the keyboard focus moved by Tab and Shift+Tab.
+package widget
+
+import (
+	gc "github.com/rthornton128/goncurses"
+)
+
+// Color pairs used by the widgets. They are initialized by the game
+var (
+	TitleColor int16 = 1
+	TextColor  int16 = 3
+)
+
+// Key codes not defined by goncurses
+const (
+	EscapeKey    gc.Key = 27
+	BackspaceKey gc.Key = 127
+)
+
+// Rect is the rectangle of the window in its own coordinates
+type Rect struct {
+	Y, X          int
+	Height, Width int
+}
+
+// Widget is the element of the dialog drawn in its rectangle of the window
+type Widget interface {
+	// PreferredHeight is the amount of lines the widget needs, 0 if it fills all of the free space
+	PreferredHeight() int
+	// SetBounds sets the rectangle of the window the widget is drawn in
+	SetBounds(bounds Rect)
+	// Draw renders the widget in the window
+	Draw(window *gc.Window)
+	// HandleKey reacts on the key pressed while the widget has focus. Returns true if the key is consumed
+	HandleKey(key gc.Key) bool
+	// Focusable checks if the widget can receive the input
+	Focusable() bool
+	// SetFocused marks the widget as the one receiving the input
+	SetFocused(focused bool)
+}
+
+// container is the widget containing the other widgets
+type container interface {
+	Children() []Widget
+}
+
+// widthHinter is the widget knowing its width, used by the row layout
+type widthHinter interface {
+	PreferredWidth() int
+}
+
+// cursorOwner is the widget showing the terminal cursor while it is focused
+type cursorOwner interface {
+	CursorPosition() (y, x int)
+}
+
+// base implements the common parts of the widgets
+type base struct {
+	bounds  Rect
+	focused bool
+}
+
+func (widget *base) SetBounds(bounds Rect) {
+	widget.bounds = bounds
+}
+
+// Bounds returns the rectangle of the window the widget is drawn in
+func (widget *base) Bounds() Rect {
+	return widget.bounds
+}
+
+func (widget *base) SetFocused(focused bool) {
+	widget.focused = focused
+}
+
+// Focused checks if the widget receives the input
+func (widget *base) Focused() bool {
+	return widget.focused
+}
+
+func (widget *base) Focusable() bool {
+	return false
+}
+
+func (widget *base) HandleKey(key gc.Key) bool {
+	return false
+}
+
+// Clip cuts the text to fit into the specified width
+func Clip(text string, width int) string {
+	runes := []rune(text)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return text
+}
+
+// Pad fills the text with spaces up to the specified width, cutting it if it is longer
+func Pad(text string, width int) string {
+	text = Clip(text, width)
+	for length := len([]rune(text)); length < width; length++ {
+		text += " "
+	}
+	return text
+}
+
+// isActivationKey checks if the key presses the button or toggles the checkbox
+func isActivationKey(key gc.Key) bool {
+	return key == gc.KEY_RETURN || key == gc.KEY_ENTER || key == ' '
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}